@@ -0,0 +1,199 @@
+package arango
+
+import (
+	"errors"
+	"regexp"
+)
+
+// Options of a new database, used in clusters. Zero values use server defaults.
+type DatabaseOptions struct {
+	// "single" to place all collections in one shard, "flexible" by default
+	Sharding          string `json:"sharding,omitempty"`
+	ReplicationFactor int    `json:"replicationFactor,omitempty"`
+	WriteConcern      int    `json:"writeConcern,omitempty"`
+}
+
+// Database information as returned by server
+type DatabaseInfo struct {
+	Name              string      `json:"name"`
+	Id                string      `json:"id"`
+	Path              string      `json:"path"`
+	System            bool        `json:"isSystem"`
+	Sharding          string      `json:"sharding"`
+	ReplicationFactor interface{} `json:"replicationFactor"`
+	WriteConcern      int         `json:"writeConcern"`
+}
+
+// Server version
+type VersionInfo struct {
+	Server  string            `json:"server"`
+	Version string            `json:"version"`
+	License string            `json:"license"`
+	Details map[string]string `json:"details"`
+}
+
+// Storage engine of server
+type EngineInfo struct {
+	Name     string                 `json:"name"`
+	Supports map[string]interface{} `json:"supports"`
+}
+
+// valid database and collection name
+var nameReg = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_\-]*$`)
+
+// systemDB returns _system database, where databases are managed
+func (s *Session) systemDB() (*Database, error) {
+	db := s.DB("_system")
+	if db == nil {
+		return nil, errors.New("Invalid db, databases are managed in _system database")
+	}
+	return db, nil
+}
+
+// refreshDBs reloads databases available to the user
+func (s *Session) refreshDBs() error {
+	var dbs Databases
+	_, err := s.nap.Get(s.host+"/_api/database/user", nil, &dbs, nil)
+	if err != nil {
+		return err
+	}
+	s.dbs.List = dbs.List
+	return nil
+}
+
+// CreateDatabase creates database with users, opts can be nil.
+//
+// Usage:
+//
+//	err := s.CreateDatabase("shop", []User{{Username: "app", Password: "secret", Active: true}}, &DatabaseOptions{Sharding: "single"})
+func (s *Session) CreateDatabase(name string, users []User, opts *DatabaseOptions) error {
+	if !nameReg.MatchString(name) {
+		return errors.New("Invalid database name")
+	}
+	db, err := s.systemDB()
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{"name": name}
+	if len(users) > 0 {
+		body["users"] = users
+	}
+	if opts != nil {
+		body["options"] = opts
+	}
+
+	var e ArangoError
+	res, err := db.send("database", "", "POST", body, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 201:
+		return s.refreshDBs()
+	case 400, 403, 409:
+		e.Code = res.Status()
+		return &e
+	default:
+		return statusError(res)
+	}
+}
+
+// DropDatabase drops database and all its data
+func (s *Session) DropDatabase(name string) error {
+	if name == "" {
+		return errors.New("Invalid empty database name")
+	}
+	db, err := s.systemDB()
+	if err != nil {
+		return err
+	}
+
+	var e ArangoError
+	res, err := db.get("database", name, "DELETE", nil, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		return s.refreshDBs()
+	case 400, 403, 404:
+		e.Code = res.Status()
+		return &e
+	default:
+		return statusError(res)
+	}
+}
+
+// Databases lists every database in server, user must have access to _system database.
+// Use AvailableDBs to list databases the user can access.
+func (s *Session) Databases() ([]string, error) {
+	db, err := s.systemDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var dbs Databases
+	var e ArangoError
+	res, err := db.get("database", "", "GET", nil, &dbs, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return dbs.List, nil
+	case 400, 403:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// Info returns information of database
+func (d *Database) Info() (*DatabaseInfo, error) {
+	var info struct {
+		Result DatabaseInfo `json:"result"`
+	}
+	res, err := d.get("database", "current", "GET", nil, &info, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return &info.Result, nil
+	case 404:
+		return nil, statusError(res)
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// Version returns server version with build details
+func (d *Database) Version() (*VersionInfo, error) {
+	var v VersionInfo
+	res, err := d.get("version", "", "GET", nil, &v, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status() != 200 {
+		return nil, statusError(res)
+	}
+	return &v, nil
+}
+
+// Engine returns storage engine of server
+func (d *Database) Engine() (*EngineInfo, error) {
+	var e EngineInfo
+	res, err := d.get("engine", "", "GET", nil, &e, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status() != 200 {
+		return nil, statusError(res)
+	}
+	return &e, nil
+}
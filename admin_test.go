@@ -0,0 +1,52 @@
+package arango
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseName(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{500, ``}}}
+	s := testDB(st).sess
+	for _, name := range []string{"a b/c", "shop`", "[shop]", "_shop", "1shop", ""} {
+		assert.NotNil(t, s.CreateDatabase(name, nil, nil), name)
+	}
+	assert.Equal(t, 0, len(st.reqs))
+	assert.Nil(t, validColName("users_2-b"))
+	assert.NotNil(t, validColName("users^"))
+}
+
+func TestCreateDB(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{200, `{"result":[]}`},
+		{201, `{"result":true}`},
+		{200, `{"result":["_system","shop"]}`},
+	}}
+	s := testDB(st).sess
+	s.dbs.List = []string{"_system"}
+	assert.Nil(t, s.CreateDB("shop", nil))
+	assert.Equal(t, "POST", st.reqs[1].Method)
+	assert.Equal(t, "/_db/_system/_api/database", st.reqs[1].URL.Path)
+	assert.Equal(t, []string{"_system", "shop"}, s.dbs.List)
+
+	// DropDB shares errors and database refresh of DropDatabase
+	st.reqs = nil
+	st.responses = []testResponse{
+		{200, `{"result":[]}`},
+		{200, `{"result":true}`},
+		{200, `{"result":["_system"]}`},
+	}
+	assert.Nil(t, s.DropDB("shop"))
+	assert.Equal(t, "DELETE", st.reqs[1].Method)
+	assert.Equal(t, "/_db/_system/_api/database/shop", st.reqs[1].URL.Path)
+	assert.Equal(t, []string{"_system"}, s.dbs.List)
+
+	st.reqs = nil
+	st.responses = []testResponse{
+		{200, `{"result":[]}`},
+		{404, `{"error":true,"code":404,"errorNum":1228,"errorMessage":"database not found"}`},
+	}
+	assert.True(t, errors.Is(s.DropDB("shop"), ErrNotFound))
+}
@@ -3,8 +3,10 @@ package arango
 import (
 	"encoding/json"
 	"errors"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // AqlObject
@@ -104,15 +106,24 @@ func (v Var) String() string {
 type Query struct {
 	// mandatory
 	Aql string `json:"query,omitempty"`
-	//Optional values Batch    int                    `json:"batchSize,omitempty"`
+	//Optional values
+	Batch    int                    `json:"batchSize,omitempty"`
 	Count    bool                   `json:"count,omitempty"`
 	BindVars map[string]interface{} `json:"bindVars,omitempty"`
 	Options  map[string]interface{} `json:"options,omitempty"`
+	// cursor time to live in seconds and query memory limit in bytes
+	Ttl         int   `json:"ttl,omitempty"`
+	MemoryLimit int64 `json:"memoryLimit,omitempty"`
+	// use query results cache, needed if cache mode is demand
+	Cache bool `json:"cache,omitempty"`
 	// opetions fullCount bool
 	// Note that the fullCount sub-attribute will only be present in the result if the query has a LIMIT clause and the LIMIT clause is actually used in the query.
 	// Control
 	Validate bool   `json:"-"`
 	ErrorMsg string `json:"errorMessage,omitempty"`
+	// Projection keeps only listed attributes of returned documents.
+	// Only applies to simple FOR doc IN ... RETURN doc queries
+	Projection []string `json:"-"`
 }
 
 func NewQuery(query string) *Query {
@@ -129,6 +140,67 @@ func NewQuery(query string) *Query {
 	}
 }
 
+// Query options
+type QueryOptions struct {
+	// Results per batch
+	BatchSize int
+	// Return total number of results in Cursor.Count
+	Count bool
+	// Return number of results without the last LIMIT in Cursor.FullCount
+	FullCount bool
+	// Time to keep cursor in server after last use
+	Ttl time.Duration
+	// Max memory used by query in bytes
+	MemoryLimit int64
+	// Return time spent in each query phase in Cursor.Profile
+	Profile bool
+	// Also return executed plan and stats of each plan node in Cursor.Data.Plan and Cursor.Data.Stats.Nodes
+	ProfileNodes bool
+	// Use query results cache, Cursor.Cached is true if results came from it
+	Cache bool
+	// Shard key value of the documents used by the query, so it runs in the single DB server holding them.
+	// Only valid in a cluster (OneShard databases or queries filtering by shard key), server must be a coordinator.
+	// Results will be incomplete if query touches documents with any other shard key value.
+	ForceOneShardAttributeValue string
+}
+
+// SetOptions sets non empty options of query
+func (q *Query) SetOptions(opts QueryOptions) {
+	if q.Options == nil {
+		q.Options = make(map[string]interface{})
+	}
+	if opts.BatchSize > 0 {
+		q.Batch = opts.BatchSize
+	}
+	if opts.Count {
+		q.Count = true
+	}
+	if opts.FullCount {
+		q.Options["fullCount"] = true
+	}
+	if opts.Ttl > 0 {
+		q.Ttl = int(opts.Ttl / time.Second)
+		if q.Ttl == 0 {
+			q.Ttl = 1
+		}
+	}
+	if opts.MemoryLimit > 0 {
+		q.MemoryLimit = opts.MemoryLimit
+	}
+	if opts.Profile {
+		q.Options["profile"] = true
+	}
+	if opts.ProfileNodes {
+		q.Options["profile"] = 2
+	}
+	if opts.Cache {
+		q.Cache = true
+	}
+	if opts.ForceOneShardAttributeValue != "" {
+		q.Options["forceOneShardAttributeValue"] = opts.ForceOneShardAttributeValue
+	}
+}
+
 func (q *Query) SetFullCount(count bool) {
 	q.Options["fullCount"] = count
 }
@@ -142,6 +214,40 @@ func (q *Query) Modify(query string) error {
 	}
 }
 
+// Project sets attributes to keep from returned documents
+func (q *Query) Project(fields ...string) {
+	q.Projection = fields
+}
+
+// matches FOR doc IN ... RETURN doc
+var simpleReturn = regexp.MustCompile(`(?is)^(\s*FOR\s+([A-Za-z_][A-Za-z0-9_]*)\s+IN\s.*\s)RETURN\s+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+
+// projected returns query rewritten to RETURN KEEP(doc, @projection), using def projection if query has none.
+func (q *Query) projected(def []string) *Query {
+	fields := q.Projection
+	if len(fields) == 0 {
+		fields = def
+	}
+	if len(fields) == 0 {
+		return q
+	}
+
+	m := simpleReturn.FindStringSubmatch(q.Aql)
+	// ignored if query has custom RETURN
+	if m == nil || m[2] != m[3] {
+		return q
+	}
+
+	pq := *q
+	pq.Aql = m[1] + "RETURN KEEP(" + m[2] + ", @projection)"
+	pq.BindVars = make(map[string]interface{})
+	for k, v := range q.BindVars {
+		pq.BindVars[k] = v
+	}
+	pq.BindVars["projection"] = fields
+	return &pq
+}
+
 // Validate query before execution
 func (q *Query) MustCheck() {
 	q.Validate = true
@@ -159,6 +265,10 @@ type AqlStruct struct {
 	nlopp uint
 	vars  []string
 	err   bool
+	// first error building query
+	errMsg string
+	// bind parameters
+	binds map[string]interface{}
 }
 
 //Generate Aql query string
@@ -181,9 +291,21 @@ func NewAqlStruct() *AqlStruct {
 	return &aq
 }
 
+// Bind sets bind parameter used in query, as @key
+func (aq *AqlStruct) Bind(key string, value interface{}) *AqlStruct {
+	if aq.binds == nil {
+		aq.binds = make(map[string]interface{})
+	}
+	aq.binds[key] = value
+	return aq
+}
+
 //Execute AqlStuct into database
 func (aq *AqlStruct) Execute(db *Database) (*Cursor, error) {
-	q := NewQuery(aq.Generate())
+	q, err := aq.Query()
+	if err != nil {
+		return nil, err
+	}
 	c, err := db.Execute(q)
 
 	return c, err
@@ -203,6 +325,43 @@ func (aq *AqlStruct) subStruct() *AqlStruct {
 	}
 }
 
+// Query returns parameterized query with bind parameters, to execute with Database.Execute
+func (aq *AqlStruct) Query() (*Query, error) {
+	if aq.err {
+		return nil, errors.New(aq.errMsg)
+	}
+	q := NewQuery(aq.Generate())
+	for k, v := range aq.binds {
+		q.BindVars[k] = v
+	}
+	return q, nil
+}
+
+var (
+	aqlVar    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	plainName = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+	// reserved words, names using them must be quoted
+	aqlKeywords = map[string]bool{
+		"FOR": true, "RETURN": true, "FILTER": true, "SEARCH": true, "SORT": true, "LIMIT": true, "LET": true,
+		"COLLECT": true, "WINDOW": true, "INSERT": true, "UPDATE": true, "REPLACE": true, "REMOVE": true,
+		"UPSERT": true, "WITH": true, "AGGREGATE": true, "ALL": true, "ANY": true, "AND": true, "OR": true,
+		"NOT": true, "IN": true, "LIKE": true, "INTO": true, "ASC": true, "DESC": true, "DISTINCT": true,
+		"GRAPH": true, "SHORTEST_PATH": true, "OUTBOUND": true, "INBOUND": true, "NONE": true, "AT": true,
+		"LEAST": true, "NULL": true, "TRUE": true, "FALSE": true,
+	}
+)
+
+// EscapeName quotes collection or attribute name with backticks if it's not a plain identifier
+//  EscapeName("order-items") out: `order-items`
+func EscapeName(name string) string {
+	if aqlVar.MatchString(name) && !aqlKeywords[strings.ToUpper(name)] {
+		return name
+	}
+	name = strings.Replace(name, "\\", "\\\\", -1)
+	name = strings.Replace(name, "`", "\\`", -1)
+	return "`" + name + "`"
+}
+
 // FOR var IN [] //
 type aqlFor struct {
 	in interface{}
@@ -219,7 +378,11 @@ func (aqf aqlFor) Generate() string {
 
 	switch aqf.in.(type) {
 	case string:
-		code += aqf.in.(string)
+		in := aqf.in.(string)
+		if plainName.MatchString(in) {
+			in = EscapeName(in)
+		}
+		code += in
 	case *AqlFunction:
 		code += aqf.in.(AqlFunction).Generate()
 	case []string:
@@ -235,6 +398,9 @@ func (aqf aqlFor) Generate() string {
 
 func (aq *AqlStruct) For(v string, in interface{}) *AqlStruct {
 	var afor aqlFor
+	if !aqlVar.MatchString(v) || aqlKeywords[strings.ToUpper(v)] {
+		return aq.fail("Invalid variable name " + v)
+	}
 	afor.v = v
 	afor.in = in
 	aq.lines = append(aq.lines, afor)
@@ -243,13 +409,17 @@ func (aq *AqlStruct) For(v string, in interface{}) *AqlStruct {
 
 // Aql Return
 type aqlReturn struct {
-	Atr Var
-	Var string
-	Ret Obj
+	Atr      Var
+	Var      string
+	Ret      Obj
+	Distinct bool
 }
 
 func (ar aqlReturn) Generate() string {
 	code := "RETURN "
+	if ar.Distinct {
+		code += "DISTINCT "
+	}
 	if ar.Var != "" {
 		code += ar.Var
 	} else {
@@ -282,6 +452,21 @@ func (aq *AqlStruct) Return(view interface{}) *AqlStruct {
 	return aq
 }
 
+// Aql Return distinct values
+// Usage:
+//  ReturnDistinct("u.city")
+//  out: RETURN DISTINCT u.city
+func (aq *AqlStruct) ReturnDistinct(view interface{}) *AqlStruct {
+	n := len(aq.lines)
+	aq.Return(view)
+	if len(aq.lines) > n {
+		ret := aq.lines[n].(aqlReturn)
+		ret.Distinct = true
+		aq.lines[n] = ret
+	}
+	return aq
+}
+
 //Aql filter add Filter()  to AqlQuery
 // Could be use like:
 //        - Filter(custom ... string)
@@ -465,6 +650,16 @@ func (aqf AqlFilter) Generate() string {
 	return code
 }
 
+// In returns filter checking field is in list bind parameter
+// Usage:
+//  Filter(In("u.name","names")).Bind("names",[]string{"Diego","Facundo"})
+//  out: FILTER u.name IN @names
+func In(field string, bindKey string) AqlFilter {
+	var f AqlFilter
+	f.Custom = field + " IN @" + bindKey
+	return f
+}
+
 // Returns AqlFilter parsing valid json string
 func FilterJSON(s string) AqlFilter {
 	var aqf AqlFilter
@@ -699,6 +894,168 @@ func (aq *AqlStruct) Collect(sentence string) *AqlStruct {
 	return aq
 }
 
+// Aql Collect count
+// Usage:
+// CollectCount("total")
+// out: COLLECT WITH COUNT INTO total
+func (aq *AqlStruct) CollectCount(into string) *AqlStruct {
+	if into == "" {
+		return aq
+	}
+	return aq.Collect("WITH COUNT INTO " + into)
+}
+
+// Aql Collect with aggregates
+// Usage:
+// GroupBy("city = u.city", "orders = COUNT(u.orders)", "total = SUM(u.amount)")
+// out: COLLECT city = u.city AGGREGATE orders = COUNT(u.orders), total = SUM(u.amount)
+func (aq *AqlStruct) GroupBy(groups string, aggregates ...string) *AqlStruct {
+	if groups == "" && len(aggregates) == 0 {
+		return aq
+	}
+	var g aqlGroup
+	g.groups = groups
+	g.aggregates = aggregates
+	g.vars = collectVars(groups)
+	for _, a := range aggregates {
+		g.aggVars = append(g.aggVars, collectVars(a)...)
+	}
+	aq.lines = append(aq.lines, g)
+	return aq
+}
+
+// Aql filter after GroupBy, expression must reference aggregate variables
+// Usage:
+// GroupBy("customer = o.customer", "orders = COUNT(1)").Having("orders > @min", map[string]interface{}{"min": 10})
+// out: COLLECT customer = o.customer AGGREGATE orders = COUNT(1) FILTER orders > @min
+func (aq *AqlStruct) Having(expr string, bindVars map[string]interface{}) *AqlStruct {
+	var g aqlGroup
+	var ok bool
+	if len(aq.lines) > 0 {
+		g, ok = aq.lines[len(aq.lines)-1].(aqlGroup)
+	}
+	if !ok {
+		return aq.fail("Having must follow GroupBy")
+	}
+
+	declared := make(map[string]bool)
+	for _, v := range g.vars {
+		declared[v] = true
+	}
+	var agg bool
+	for _, v := range aqlIdentifiers(expr) {
+		if declared[v] {
+			continue
+		}
+		found := false
+		for _, a := range g.aggVars {
+			if a == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return aq.fail("Having references undeclared variable " + v)
+		}
+		agg = true
+	}
+	if !agg {
+		return aq.fail("Having must reference an aggregate variable")
+	}
+
+	for k, v := range bindVars {
+		aq.Bind(k, v)
+	}
+	aq.lines = append(aq.lines, AqlFilter{Custom: expr})
+	return aq
+}
+
+// fail keeps first error building query, returned by Execute
+func (aq *AqlStruct) fail(msg string) *AqlStruct {
+	if !aq.err {
+		aq.err = true
+		aq.errMsg = msg
+	}
+	return aq
+}
+
+type aqlGroup struct {
+	groups     string
+	aggregates []string
+	// declared variables
+	vars    []string
+	aggVars []string
+}
+
+func (g aqlGroup) Generate() string {
+	code := "COLLECT"
+	if g.groups != "" {
+		code += " " + g.groups
+	}
+	if len(g.aggregates) > 0 {
+		code += " AGGREGATE " + strings.Join(g.aggregates, ", ")
+	}
+	return code
+}
+
+// collectVars returns variables assigned in "a = x, b = y INTO g" expressions
+func collectVars(s string) []string {
+	var vars []string
+	if m := aqlInto.FindStringSubmatch(s); m != nil {
+		vars = append(vars, m[1])
+		s = s[:strings.Index(s, m[0])]
+	}
+	depth, start := 0, 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			switch s[i] {
+			case '(', '[', '{':
+				depth++
+				continue
+			case ')', ']', '}':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		part := s[start:i]
+		start = i + 1
+		if eq := strings.Index(part, "="); eq > 0 {
+			vars = append(vars, strings.TrimSpace(part[:eq]))
+		}
+	}
+	return vars
+}
+
+var (
+	aqlInto    = regexp.MustCompile(`(?i)\bINTO\s+([A-Za-z_][A-Za-z0-9_]*)`)
+	aqlStrings = regexp.MustCompile(`'(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*"`)
+	aqlIdent   = regexp.MustCompile(`[@.]?\b[A-Za-z_][A-Za-z0-9_]*(\s*\()?`)
+)
+
+// aqlIdentifiers returns variables used in expression, skipping bind parameters, attributes, functions and keywords
+func aqlIdentifiers(expr string) []string {
+	var ids []string
+	expr = aqlStrings.ReplaceAllString(expr, "''")
+	for _, m := range aqlIdent.FindAllStringSubmatch(expr, -1) {
+		id := m[0]
+		if id[0] == '@' || id[0] == '.' || m[1] != "" {
+			continue
+		}
+		switch strings.ToUpper(id) {
+		case "AND", "OR", "NOT", "IN", "LIKE", "NULL", "TRUE", "FALSE":
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 type AqlCollect struct {
 	Sentence string `json:"collect"`
 }
@@ -921,6 +1278,21 @@ func (aqr AqlRemove) Generate() string {
 	return code
 }
 
+// Raw adds fragment to query as it is, for AQL not covered by AqlStruct
+func (aq *AqlStruct) Raw(fragment string) *AqlStruct {
+	if fragment == "" {
+		return aq
+	}
+	aq.lines = append(aq.lines, aqlRaw(fragment))
+	return aq
+}
+
+type aqlRaw string
+
+func (r aqlRaw) Generate() string {
+	return string(r)
+}
+
 // Aql functions
 type AqlFunction struct {
 	Name   string
@@ -0,0 +1,129 @@
+package arango
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjection(t *testing.T) {
+	q := NewQuery("FOR u IN users FILTER u.age > @age RETURN u")
+	q.BindVars["age"] = 21
+	q.Project("name", "age")
+
+	p := q.projected(nil)
+	assert.Equal(t, "FOR u IN users FILTER u.age > @age RETURN KEEP(u, @projection)", p.Aql)
+	assert.Equal(t, []string{"name", "age"}, p.BindVars["projection"])
+	// original query is untouched
+	assert.Equal(t, "FOR u IN users FILTER u.age > @age RETURN u", q.Aql)
+	assert.Nil(t, q.BindVars["projection"])
+
+	// custom return is ignored
+	q = NewQuery("FOR u IN users RETURN u.name")
+	assert.Equal(t, q, q.projected([]string{"name"}))
+
+	// no projection
+	q = NewQuery("FOR u IN users RETURN u")
+	assert.Equal(t, q, q.projected(nil))
+}
+
+func TestAqlHelpers(t *testing.T) {
+	aq := NewAqlStruct().For("u", "users").Filter(In("u.name", "names")).Raw("LIMIT 2").ReturnDistinct("u")
+	aq.Bind("names", []string{"a", "b"})
+	assert.Equal(t, "FOR u IN users FILTER u.name IN @names LIMIT 2 RETURN DISTINCT u", strings.Join(strings.Fields(aq.Generate()), " "))
+	assert.Equal(t, []string{"a", "b"}, aq.binds["names"])
+
+	aq = NewAqlStruct().For("u", "users").CollectCount("total").Return("total")
+	assert.Equal(t, "FOR u IN users COLLECT WITH COUNT INTO total RETURN total", strings.Join(strings.Fields(aq.Generate()), " "))
+
+	aq = NewAqlStruct().For("o", "orders").GroupBy("customer = o.customer", "orders = COUNT(1)", "total = SUM(o.amount)").
+		Having("orders > @min && LOWER(customer) != 'x'", map[string]interface{}{"min": 10}).Return("customer")
+	assert.False(t, aq.err)
+	assert.Equal(t, "FOR o IN orders COLLECT customer = o.customer AGGREGATE orders = COUNT(1), total = SUM(o.amount) FILTER orders > @min && LOWER(customer) != 'x' RETURN customer", strings.Join(strings.Fields(aq.Generate()), " "))
+	assert.Equal(t, 10, aq.binds["min"])
+
+	aq = NewAqlStruct().For("o", "orders").GroupBy("customer = o.customer", "orders = COUNT(1)").Having("o.amount > 1", nil)
+	assert.Equal(t, "Having references undeclared variable o", aq.errMsg)
+	aq = NewAqlStruct().For("o", "orders").GroupBy("customer = o.customer INTO g", "orders = COUNT(1)").Having("LENGTH(g) > 1", nil)
+	assert.Equal(t, "Having must reference an aggregate variable", aq.errMsg)
+}
+
+func TestAqlEscape(t *testing.T) {
+	assert.Equal(t, "users", EscapeName("users"))
+	assert.Equal(t, "`order-items`", EscapeName("order-items"))
+	assert.Equal(t, "`graph`", EscapeName("graph"))
+	assert.Equal(t, "`a\\`b`", EscapeName("a`b"))
+
+	aq := NewAqlStruct().For("u", "order-items").Filter("u.age > @age").Bind("age", 21).Limit(10).Return("u")
+	q, err := aq.Query()
+	assert.Nil(t, err)
+	assert.Equal(t, "FOR u IN `order-items` FILTER u.age > @age LIMIT 10 RETURN u", strings.Join(strings.Fields(q.Aql), " "))
+	assert.Equal(t, 21, q.BindVars["age"])
+
+	_, err = NewAqlStruct().For("for", "users").Return("u").Query()
+	assert.NotNil(t, err)
+}
+
+func TestEncodeDate(t *testing.T) {
+	d := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.FixedZone("UTC-3", -3*3600))
+	assert.Equal(t, "2020-01-02T06:04:05.006Z", encodeDate(d, DateISO))
+	assert.Equal(t, int64(1577945045006), encodeDate(d, DateEpochMillis))
+}
+
+func TestQueryOptions(t *testing.T) {
+	q := NewQuery("FOR u IN users LIMIT 10 RETURN u")
+	q.SetOptions(QueryOptions{BatchSize: 50, Count: true, FullCount: true, Ttl: 90 * time.Second, MemoryLimit: 1 << 20, Profile: true})
+	b, err := json.Marshal(q)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"query":"FOR u IN users LIMIT 10 RETURN u","batchSize":50,"count":true,"options":{"fullCount":true,"profile":true},"ttl":90,"memoryLimit":1048576}`, string(b))
+
+	c := testCursor(t, `{"result":[],"hasMore":false,"extra":{"profile":{"parsing":0.001}}}`)
+	assert.Equal(t, 0.001, c.Profile()["parsing"])
+	assert.Equal(t, 0, len(testCursor(t, `{"result":[]}`).Profile()))
+
+	q = NewQuery("FOR u IN users RETURN u")
+	q.SetOptions(QueryOptions{ProfileNodes: true, Cache: true})
+	b, _ = json.Marshal(q)
+	assert.Equal(t, `{"query":"FOR u IN users RETURN u","options":{"profile":2},"cache":true}`, string(b))
+
+	c = testCursor(t, `{"result":[],"hasMore":false,"extra":{"stats":{"nodes":[{"id":1,"calls":1,"items":3,"runtime":0.002}]},"plan":{"nodes":[{"type":"IndexNode","id":1,"indexes":[{"id":"12","name":"idx_name"}]}]}}}`)
+	assert.Equal(t, []NodeStats{{Id: 1, Calls: 1, Items: 3, Runtime: 0.002}}, c.Data.Stats.Nodes)
+	assert.Equal(t, []string{"idx_name"}, c.Data.Plan.IndexesUsed())
+}
+
+type warnHook struct {
+	recordHook
+	warnings []string
+}
+
+func (h *warnHook) OnWarning(msg string) { h.warnings = append(h.warnings, msg) }
+
+func TestSessionProjection(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{201, `{"result":[],"hasMore":false}`}}}
+	db := testDB(st)
+	h := &warnHook{}
+	db.sess.AddHook(h)
+	db.sess.SetProjection("name")
+
+	// session projection only applies to simple query helpers
+	_, err := db.Execute(NewQuery("FOR u IN users RETURN u"))
+	assert.Nil(t, err)
+	var body map[string]interface{}
+	json.NewDecoder(st.reqs[0].Body).Decode(&body)
+	assert.Equal(t, "FOR u IN users RETURN u", body["query"])
+	assert.Equal(t, 0, len(h.warnings))
+
+	q := NewQuery("FOR u IN users RETURN u.name")
+	q.Project("name")
+	db.Execute(q)
+	assert.Equal(t, []string{"projection ignored, query has custom RETURN"}, h.warnings)
+
+	col := &Collection{db: db, Name: "users", Type: 2}
+	col.All(0, 0)
+	body = nil
+	json.NewDecoder(st.reqs[2].Body).Decode(&body)
+	assert.Equal(t, "FOR doc IN @@col RETURN KEEP(doc, @projection)", body["query"])
+}
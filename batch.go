@@ -0,0 +1,204 @@
+package arango
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+
+	nap "github.com/diegogub/napping"
+)
+
+// Batch queues operations sent together in a single /_api/batch request.
+// Usage:
+//
+//	b := db.Batch()
+//	save := b.Save("users", &user)
+//	b.Patch("orders", "123", map[string]interface{}{"paid": true})
+//	err := b.Send()
+//	if save.Err != nil ...
+type Batch struct {
+	db  *Database
+	ops []*BatchOp
+}
+
+// BatchOp is a queued operation, Status and Err are set by Batch.Send
+type BatchOp struct {
+	Method   string
+	Resource string
+	payload  interface{}
+	result   interface{}
+
+	Status int
+	// *ArangoError if operation failed
+	Err error
+}
+
+// Batch returns an empty batch of database operations
+func (d *Database) Batch() *Batch {
+	return &Batch{db: d}
+}
+
+// Add queues request to resource relative to database api, like "document/users", decoding response into result
+func (b *Batch) Add(method string, resource string, payload, result interface{}) *BatchOp {
+	op := &BatchOp{Method: method, Resource: resource, payload: payload, result: result}
+	b.ops = append(b.ops, op)
+	return op
+}
+
+// Save queues insert of doc into collection, doc gets _id, _key and _rev
+func (b *Batch) Save(col string, doc interface{}) *BatchOp {
+	return b.Add("POST", "document/"+col, doc, doc)
+}
+
+// Replace queues replace of document key
+func (b *Batch) Replace(col string, key string, doc interface{}) *BatchOp {
+	return b.Add("PUT", "document/"+col+"/"+key, doc, doc)
+}
+
+// Patch queues patch of document key
+func (b *Batch) Patch(col string, key string, patch interface{}) *BatchOp {
+	return b.Add("PATCH", "document/"+col+"/"+key, patch, nil)
+}
+
+// Delete queues removal of document key
+func (b *Batch) Delete(col string, key string) *BatchOp {
+	return b.Add("DELETE", "document/"+col+"/"+key, nil, nil)
+}
+
+// Len returns number of queued operations
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Send sends queued operations and sets their results. Returned error is about the batch request,
+// errors of each operation are in its Err. Batch is emptied.
+func (b *Batch) Send() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	ops := b.ops
+	b.ops = nil
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for i, op := range ops {
+		if err := writeBatchPart(w, i, op); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "multipart/form-data; boundary="+w.Boundary())
+	var e ArangoError
+	res, err := b.db.withRetryIf(b.db.context(), "POST", "batch", false, func() (*nap.Response, error) {
+		req := nap.Request{
+			Method:              "POST",
+			Url:                 b.db.buildRequest("batch", ""),
+			Payload:             bytes.NewBuffer(body.Bytes()),
+			RawPayload:          true,
+			Error:               &e,
+			Header:              &header,
+			CaptureResponseBody: true,
+		}
+		return b.db.napSession(b.db.context()).Send(&req)
+	})
+	if err != nil {
+		return ctxErr(b.db.context(), err)
+	}
+
+	switch res.Status() {
+	case 200:
+	case 400, 405:
+		e.Code = res.Status()
+		return &e
+	default:
+		return statusError(res)
+	}
+
+	boundary := w.Boundary()
+	if _, params, err := mime.ParseMediaType(responseHeader(res).Get("Content-Type")); err == nil && params["boundary"] != "" {
+		boundary = params["boundary"]
+	}
+	return readBatchParts([]byte(res.RawText()), boundary, ops)
+}
+
+// writeBatchPart writes op as an http request part with content id i
+func writeBatchPart(w *multipart.Writer, i int, op *BatchOp) error {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", "application/x-arango-batchpart")
+	h.Set("Content-Id", strconv.Itoa(i))
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	var payload []byte
+	if op.payload != nil {
+		if payload, err = json.Marshal(op.payload); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(part, "%s /_api/%s HTTP/1.1\r\n\r\n", op.Method, op.Resource)
+	_, err = part.Write(payload)
+	return err
+}
+
+// readBatchParts sets status and results of ops from batch response parts
+func readBatchParts(body []byte, boundary string, ops []*BatchOp) error {
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for n := 0; ; n++ {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		i := n
+		if id, err := strconv.Atoi(part.Header.Get("Content-Id")); err == nil {
+			i = id
+		}
+		if i < 0 || i >= len(ops) {
+			return errors.New("Invalid batch part id " + strconv.Itoa(i))
+		}
+		res, err := http.ReadResponse(bufio.NewReader(part), nil)
+		if err != nil {
+			return err
+		}
+		rbody, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		op := ops[i]
+		op.Status = res.StatusCode
+		if res.StatusCode >= 400 {
+			e := &ArangoError{}
+			json.Unmarshal(rbody, e)
+			e.Code = res.StatusCode
+			e.Header = res.Header
+			op.Err = e
+			continue
+		}
+		if op.result != nil && len(bytes.TrimSpace(rbody)) > 0 {
+			op.Err = json.Unmarshal(rbody, op.result)
+		}
+	}
+	for _, op := range ops {
+		if op.Status == 0 {
+			op.Err = errors.New("Missing batch response of " + op.Method + " " + op.Resource)
+		}
+	}
+	return nil
+}
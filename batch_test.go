@@ -0,0 +1,53 @@
+package arango
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchParts(t *testing.T) {
+	var b Batch
+	var doc Document
+	save := b.Save("users", &doc)
+	del := b.Delete("users", "x")
+	missing := b.Patch("users", "y", map[string]int{"n": 1})
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for i, op := range b.ops {
+		assert.Nil(t, writeBatchPart(w, i, op))
+	}
+	w.Close()
+	assert.True(t, strings.Contains(body.String(), "POST /_api/document/users HTTP/1.1\r\n\r\n{}"))
+	assert.True(t, strings.Contains(body.String(), "Content-Id: 1"))
+
+	resp := "--b\r\nContent-Type: application/x-arango-batchpart\r\nContent-Id: 1\r\n\r\nHTTP/1.1 404 Not Found\r\nContent-Type: application/json\r\n\r\n{\"errorNum\":1202,\"errorMessage\":\"not found\"}\r\n" +
+		"--b\r\nContent-Type: application/x-arango-batchpart\r\nContent-Id: 0\r\n\r\nHTTP/1.1 202 Accepted\r\n\r\n{\"_id\":\"users/1\",\"_key\":\"1\",\"_rev\":\"_abc_\"}   \r\n--b--\r\n"
+	assert.Nil(t, readBatchParts([]byte(resp), "b", b.ops))
+	assert.Nil(t, save.Err)
+	assert.Equal(t, "users/1", doc.Id)
+	assert.True(t, errors.Is(del.Err, ErrNotFound))
+	assert.Equal(t, 1202, del.Err.(*ArangoError).ErrorNum)
+	assert.NotNil(t, missing.Err)
+}
+
+func TestBatchRetry(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{503, ``}}}
+	db := testDB(st)
+	db.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond, RetryWrites: true})
+	b := db.Batch()
+	b.Save("users", map[string]int{"n": 1})
+	assert.NotNil(t, b.Send())
+	assert.Equal(t, 2, len(st.reqs))
+	first, _ := io.ReadAll(st.reqs[0].Body)
+	retry, _ := io.ReadAll(st.reqs[1].Body)
+	assert.True(t, len(first) > 0)
+	assert.Equal(t, string(first), string(retry))
+}
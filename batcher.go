@@ -0,0 +1,128 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ReadBatcher coalesces concurrent single document reads into one GetMany request.
+// Reads are buffered during window or until max reads are queued.
+type ReadBatcher struct {
+	col    *Collection
+	window time.Duration
+	max    int
+
+	mu      sync.Mutex
+	pending []*batchRead
+	timer   *time.Timer
+}
+
+type batchRead struct {
+	key  string
+	doc  interface{}
+	done chan error
+}
+
+// single document result of GetMany
+type batchDoc struct {
+	Error   bool   `json:"error"`
+	Code    int    `json:"code"`
+	Num     int    `json:"errorNum"`
+	Message string `json:"errorMessage"`
+}
+
+// NewReadBatcher returns batcher for collection reads
+func (col *Collection) NewReadBatcher(window time.Duration, max int) *ReadBatcher {
+	if window <= 0 {
+		window = 2 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 100
+	}
+	return &ReadBatcher{col: col, window: window, max: max}
+}
+
+// Get queues read of document and waits until the batch is dispatched
+func (b *ReadBatcher) Get(key string, doc interface{}) error {
+	if key == "" {
+		return errors.New("Key must not be empty")
+	}
+	r := &batchRead{key: key, doc: doc, done: make(chan error, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, r)
+	if len(b.pending) >= b.max {
+		batch := b.take()
+		b.mu.Unlock()
+		go b.dispatch(batch)
+	} else {
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.window, b.flush)
+		}
+		b.mu.Unlock()
+	}
+
+	return <-r.done
+}
+
+func (b *ReadBatcher) flush() {
+	b.mu.Lock()
+	batch := b.take()
+	b.mu.Unlock()
+	b.dispatch(batch)
+}
+
+// take returns pending reads, must hold lock
+func (b *ReadBatcher) take() []*batchRead {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.pending
+	b.pending = nil
+	return batch
+}
+
+func (b *ReadBatcher) dispatch(batch []*batchRead) {
+	if len(batch) == 0 {
+		return
+	}
+	keys := make([]string, len(batch))
+	for i, r := range batch {
+		keys[i] = r.key
+	}
+
+	var docs []json.RawMessage
+	err := b.col.GetMany(keys, &docs)
+	if err == nil && len(docs) != len(batch) {
+		err = errors.New("Invalid number of documents returned")
+	}
+	if err != nil {
+		for _, r := range batch {
+			r.done <- err
+		}
+		return
+	}
+
+	for i, r := range batch {
+		var d batchDoc
+		err = json.Unmarshal(docs[i], &d)
+		if err == nil && d.Error {
+			err = d.err()
+		}
+		if err == nil {
+			err = json.Unmarshal(docs[i], r.doc)
+		}
+		r.done <- err
+	}
+}
+
+// err returns error of document, like single reads do
+func (d batchDoc) err() error {
+	if d.Num == 1202 {
+		return ErrDocumentNotFound
+	}
+	return &ArangoError{Code: d.Code, ErrorNum: d.Num, Message: d.Message}
+}
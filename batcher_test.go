@@ -0,0 +1,47 @@
+package arango
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBatcher(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{200, `[{"_key":"a","name":"x"},{"error":true,"errorNum":1202,"errorMessage":"document not found"}]`},
+	}}
+	col := &Collection{db: testDB(st), Name: "users", Type: 2}
+	b := col.NewReadBatcher(time.Second, 2)
+
+	var doc struct {
+		Name string `json:"name"`
+	}
+	var wg sync.WaitGroup
+	var errA, errB error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errA = b.Get("a", &doc)
+	}()
+	// reads are sent in order, a is queued first
+	for {
+		b.mu.Lock()
+		n := len(b.pending)
+		b.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	errB = b.Get("b", &struct{}{})
+	wg.Wait()
+
+	assert.Nil(t, errA)
+	assert.Equal(t, "x", doc.Name)
+	assert.Equal(t, ErrDocumentNotFound, errB)
+	assert.True(t, errors.Is(errB, ErrNotFound))
+	assert.Equal(t, 1, len(st.reqs))
+}
@@ -0,0 +1,359 @@
+package arango
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	nap "github.com/diegogub/napping"
+)
+
+// ChangeType is the WAL marker type of a change
+type ChangeType int
+
+const (
+	// document inserted, updated or replaced, WAL does not tell them apart
+	ChangeUpsert ChangeType = 2300
+	// document removed, Doc only has _key and _rev
+	ChangeRemove ChangeType = 2302
+)
+
+// transaction markers, changes of a transaction are delivered on commit
+const (
+	walBegin  ChangeType = 2200
+	walCommit ChangeType = 2201
+	walAbort  ChangeType = 2202
+)
+
+// ChangeEvent is a document change read from the write ahead log
+type ChangeEvent struct {
+	Tick string
+	Type ChangeType
+	// collection name, empty if server did not send it and collection is not in FeedOptions
+	Collection string
+	// globally unique id of collection
+	CollectionId string
+	Key          string
+	Rev          string
+	// transaction id, "0" outside transactions
+	TransactionId string
+	Doc           json.RawMessage
+}
+
+// Decode decodes changed document into r
+func (e ChangeEvent) Decode(r interface{}) error {
+	return json.Unmarshal(e.Doc, r)
+}
+
+// Change feed options
+type FeedOptions struct {
+	// Deliver changes after tick, last tick of server if empty. Use ChangeFeed.Tick to resume a feed.
+	From string
+	// Only deliver changes of collections
+	Collections []string
+	// Wait between requests when there are no changes, default 1s
+	Poll time.Duration
+	// Max response size in bytes
+	ChunkSize int
+}
+
+// ChangeFeed delivers document changes of database on Events, until its context is done or Close is called.
+// Changes made in a transaction are delivered together once it commits, and never if it's aborted.
+// Tick stays before open transactions, so a resumed feed may deliver again changes delivered after them.
+// Usage:
+//
+//	feed, err := db.Tail(ctx, FeedOptions{Collections: []string{"users"}})
+//	for ev := range feed.Events {
+//		...
+//	}
+//	err = feed.Err()
+type ChangeFeed struct {
+	Events <-chan ChangeEvent
+
+	db     *Database
+	opts   FeedOptions
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	// globally unique id to name of filtered collections
+	cols map[string]string
+	// tick to fetch from and open transactions by id, only used by run
+	from string
+	open map[string]*walTx
+
+	mu   sync.Mutex
+	tick string
+	err  error
+}
+
+// changes of an open transaction
+type walTx struct {
+	begin string
+	evs   []ChangeEvent
+}
+
+// change to deliver and tick to resume from once it's delivered
+type feedEvent struct {
+	ChangeEvent
+	resume string
+}
+
+// wal/tail line
+type walMarker struct {
+	Tick string          `json:"tick"`
+	Type ChangeType      `json:"type"`
+	Cuid string          `json:"cuid"`
+	Name string          `json:"cname"`
+	Tid  string          `json:"tid"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Tail starts a change feed of database documents, built on /_api/wal/tail.
+func (d *Database) Tail(ctx context.Context, opts FeedOptions) (*ChangeFeed, error) {
+	if opts.Poll <= 0 {
+		opts.Poll = time.Second
+	}
+	f := &ChangeFeed{db: d, opts: opts, tick: opts.From, done: make(chan struct{})}
+
+	if len(opts.Collections) > 0 {
+		f.cols = make(map[string]string)
+		for _, name := range opts.Collections {
+			var props struct {
+				Id string `json:"globallyUniqueId"`
+			}
+			res, err := d.get("collection", name+"/properties", "GET", nil, &props, nil)
+			if err != nil {
+				return nil, err
+			}
+			if res.Status() != 200 {
+				return nil, statusError(res)
+			}
+			f.cols[props.Id] = name
+		}
+	}
+	if f.tick == "" {
+		state, err := d.LoggerState()
+		if err != nil {
+			return nil, err
+		}
+		f.tick = state.State.LastTick
+	}
+	f.from = f.tick
+
+	events := make(chan ChangeEvent)
+	f.Events = events
+	f.ctx, f.cancel = context.WithCancel(ctx)
+	go f.run(events)
+	return f, nil
+}
+
+// Tick returns tick of last delivered event, or the starting tick
+func (f *ChangeFeed) Tick() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tick
+}
+
+// Err returns error that stopped the feed, nil if it was closed or its context is done
+func (f *ChangeFeed) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// Close stops the feed and waits until Events is closed
+func (f *ChangeFeed) Close() {
+	f.cancel()
+	<-f.done
+}
+
+func (f *ChangeFeed) run(events chan<- ChangeEvent) {
+	defer close(f.done)
+	defer close(events)
+
+	for {
+		evs, last, more, err := f.fetch()
+		if err != nil {
+			if f.ctx.Err() == nil {
+				f.mu.Lock()
+				f.err = err
+				f.mu.Unlock()
+			}
+			return
+		}
+		for _, ev := range evs {
+			select {
+			case events <- ev.ChangeEvent:
+			case <-f.ctx.Done():
+				return
+			}
+			f.mu.Lock()
+			f.tick = ev.resume
+			f.mu.Unlock()
+		}
+		// skipped markers of other collections are not read again
+		if last != "" {
+			f.from = last
+			f.mu.Lock()
+			f.tick = f.safeTick(last)
+			f.mu.Unlock()
+		}
+		if more {
+			continue
+		}
+		select {
+		case <-time.After(f.opts.Poll):
+		case <-f.ctx.Done():
+			return
+		}
+	}
+}
+
+// fetch reads changes after from tick, returning last tick included in response and if there are more
+func (f *ChangeFeed) fetch() ([]feedEvent, string, bool, error) {
+	params := url.Values{}
+	params.Set("from", f.from)
+	if f.opts.ChunkSize > 0 {
+		params.Set("chunkSize", strconv.Itoa(f.opts.ChunkSize))
+	}
+	header := http.Header{}
+	var e ArangoError
+	res, err := f.db.observe("GET", "wal/tail", 0, func() (*nap.Response, error) {
+		return f.db.napSession(f.ctx).Send(&nap.Request{
+			Method:              "GET",
+			Url:                 f.db.buildRequest("wal/tail?"+params.Encode(), ""),
+			Error:               &e,
+			Header:              &header,
+			CaptureResponseBody: true,
+		})
+	})
+	if err != nil {
+		return nil, "", false, ctxErr(f.ctx, err)
+	}
+
+	switch res.Status() {
+	case 200, 204:
+	default:
+		e.Code = res.Status()
+		return nil, "", false, &e
+	}
+	h := responseHeader(res)
+	evs, err := f.parse([]byte(res.RawText()))
+	return evs, h.Get("x-arango-replication-lastincluded"), h.Get("x-arango-replication-checkmore") == "true", err
+}
+
+// parse decodes document markers of wal/tail response, one JSON object per line.
+// Changes of transactions are kept in open until their commit marker.
+func (f *ChangeFeed) parse(body []byte) ([]feedEvent, error) {
+	var evs []feedEvent
+	sc := bufio.NewScanner(bytes.NewReader(body))
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var m walMarker
+		if err := json.Unmarshal(line, &m); err != nil {
+			return nil, err
+		}
+		if m.Tick != "" {
+			f.from = m.Tick
+		}
+
+		switch m.Type {
+		case walBegin:
+			if f.open == nil {
+				f.open = make(map[string]*walTx)
+			}
+			f.open[m.Tid] = &walTx{begin: m.Tick}
+			continue
+		case walAbort:
+			delete(f.open, m.Tid)
+			continue
+		case walCommit:
+			tx, ok := f.open[m.Tid]
+			if !ok {
+				continue
+			}
+			delete(f.open, m.Tid)
+			// resuming before the last change reads the transaction again
+			before := f.safeTick(prevTick(tx.begin))
+			for i, ev := range tx.evs {
+				resume := before
+				if i == len(tx.evs)-1 {
+					resume = f.safeTick(m.Tick)
+				}
+				evs = append(evs, feedEvent{ev, resume})
+			}
+			continue
+		case ChangeUpsert, ChangeRemove:
+		default:
+			continue
+		}
+
+		name := m.Name
+		if f.cols != nil {
+			n, ok := f.cols[m.Cuid]
+			if !ok {
+				continue
+			}
+			name = n
+		}
+		var meta struct {
+			Key string `json:"_key"`
+			Rev string `json:"_rev"`
+		}
+		if err := json.Unmarshal(m.Data, &meta); err != nil {
+			return nil, errors.New("Invalid change marker at tick " + m.Tick)
+		}
+		ev := ChangeEvent{
+			Tick:          m.Tick,
+			Type:          m.Type,
+			Collection:    name,
+			CollectionId:  m.Cuid,
+			Key:           meta.Key,
+			Rev:           meta.Rev,
+			TransactionId: m.Tid,
+			Doc:           m.Data,
+		}
+		// transactions begun before the feed started are delivered as they come
+		if tx, ok := f.open[m.Tid]; ok {
+			tx.evs = append(tx.evs, ev)
+			continue
+		}
+		evs = append(evs, feedEvent{ev, f.safeTick(m.Tick)})
+	}
+	return evs, sc.Err()
+}
+
+// safeTick returns tick, or the tick before the oldest open transaction if it began earlier
+func (f *ChangeFeed) safeTick(tick string) string {
+	t, err := strconv.ParseUint(tick, 10, 64)
+	if err != nil {
+		return tick
+	}
+	for _, tx := range f.open {
+		b, err := strconv.ParseUint(prevTick(tx.begin), 10, 64)
+		if err == nil && b < t {
+			t = b
+		}
+	}
+	return strconv.FormatUint(t, 10)
+}
+
+// prevTick returns the tick before tick, from is exclusive so resuming from it reads tick again
+func prevTick(tick string) string {
+	t, err := strconv.ParseUint(tick, 10, 64)
+	if err != nil || t == 0 {
+		return tick
+	}
+	return strconv.FormatUint(t-1, 10)
+}
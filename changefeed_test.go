@@ -0,0 +1,63 @@
+package arango
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeFeedParse(t *testing.T) {
+	body := `{"tick":"10","type":2300,"cuid":"h1","cname":"users","tid":"0","data":{"_key":"a","_rev":"r1","name":"x"}}
+{"tick":"11","type":2001,"cuid":"h1","data":{}}
+{"tick":"12","type":2300,"cuid":"h2","tid":"0","data":{"_key":"b","_rev":"r2"}}
+{"tick":"13","type":2302,"cuid":"h1","tid":"5","data":{"_key":"a","_rev":"r3"}}
+`
+	f := &ChangeFeed{}
+	evs, err := f.parse([]byte(body))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(evs))
+	assert.Equal(t, "users", evs[0].Collection)
+	var doc struct {
+		Name string `json:"name"`
+	}
+	assert.Nil(t, evs[0].Decode(&doc))
+	assert.Equal(t, "x", doc.Name)
+	assert.Equal(t, "13", f.from)
+
+	f.cols = map[string]string{"h1": "users"}
+	evs, err = f.parse([]byte(body))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(evs))
+	assert.Equal(t, ChangeEvent{Tick: "13", Type: ChangeRemove, Collection: "users", CollectionId: "h1", Key: "a", Rev: "r3", TransactionId: "5", Doc: json.RawMessage(`{"_key":"a","_rev":"r3"}`)}, evs[1].ChangeEvent)
+	assert.Equal(t, "13", evs[1].resume)
+}
+
+func TestChangeFeedTransactions(t *testing.T) {
+	f := &ChangeFeed{}
+	evs, err := f.parse([]byte(`{"tick":"20","type":2200,"tid":"7"}
+{"tick":"21","type":2300,"cuid":"h1","tid":"7","data":{"_key":"a","_rev":"r1"}}
+{"tick":"22","type":2200,"tid":"8"}
+{"tick":"23","type":2300,"cuid":"h1","tid":"8","data":{"_key":"b","_rev":"r2"}}
+{"tick":"24","type":2300,"cuid":"h1","tid":"0","data":{"_key":"c","_rev":"r3"}}
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(evs))
+	assert.Equal(t, "c", evs[0].Key)
+	// resuming must read open transactions again
+	assert.Equal(t, "19", evs[0].resume)
+
+	// transactions can end in a later response
+	evs, err = f.parse([]byte(`{"tick":"25","type":2300,"cuid":"h1","tid":"7","data":{"_key":"d","_rev":"r4"}}
+{"tick":"26","type":2202,"tid":"8"}
+{"tick":"27","type":2201,"tid":"7"}
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(evs))
+	assert.Equal(t, "a", evs[0].Key)
+	assert.Equal(t, "19", evs[0].resume)
+	assert.Equal(t, "d", evs[1].Key)
+	assert.Equal(t, "27", evs[1].resume)
+	assert.Equal(t, 0, len(f.open))
+	assert.Equal(t, "30", f.safeTick("30"))
+}
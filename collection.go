@@ -2,7 +2,16 @@ package arango
 
 // TODO Must Implement revision control
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	nap "github.com/diegogub/napping"
 )
@@ -22,6 +31,31 @@ type CollectionOptions struct {
 	// Cluster
 	Shards    int      `json:"numberOfShards,omitempty"`
 	ShardKeys []string `json:"shardKeys,omitempty"`
+	// Document validation
+	Schema *CollectionSchema `json:"schema,omitempty"`
+	// Attributes computed by the server on write
+	ComputedValues []ComputedValue `json:"computedValues,omitempty"`
+}
+
+// Computed value of a collection, set on documents from an AQL expression
+type ComputedValue struct {
+	Name string `json:"name"`
+	// AQL RETURN expression, document is @doc
+	Expression string `json:"expression"`
+	// Replace value sent by client
+	Overwrite bool `json:"overwrite"`
+	// insert, update and/or replace, all if empty
+	ComputeOn     []string `json:"computeOn,omitempty"`
+	KeepNull      bool     `json:"keepNull"`
+	FailOnWarning bool     `json:"failOnWarning"`
+}
+
+// JSON Schema validation of collection documents
+type CollectionSchema struct {
+	Rule map[string]interface{} `json:"rule"`
+	// none, new, moderate or strict
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 func NewCollectionOptions(name string, sync bool) *CollectionOptions {
@@ -72,6 +106,26 @@ func (opt *CollectionOptions) Shard(num int) {
 	opt.Shards = num
 }
 
+// KeyGenerator sets key generator: traditional, autoincrement, uuid or padded.
+// Increment and offset are only used by autoincrement.
+func (opt *CollectionOptions) KeyGenerator(typ string, allowUserKeys bool, increment, offset int) error {
+	switch typ {
+	case "traditional", "autoincrement", "uuid", "padded":
+	default:
+		return errors.New("Invalid key generator " + typ)
+	}
+	opt.Keys = map[string]interface{}{"type": typ, "allowUserKeys": allowUserKeys}
+	if typ == "autoincrement" {
+		if increment > 0 {
+			opt.Keys["increment"] = increment
+		}
+		if offset > 0 {
+			opt.Keys["offset"] = offset
+		}
+	}
+	return nil
+}
+
 func (opt *CollectionOptions) ShardKey(keys []string) {
 	if len(keys) == 0 {
 		return
@@ -85,6 +139,7 @@ func (opt *CollectionOptions) ShardKey(keys []string) {
 // Basic Collection struct
 type Collection struct {
 	db     *Database `json:"db"`
+	Id     string    `json:"id"`
 	Name   string    `json:"name"`
 	System bool      `json:"isSystem"`
 	Status int       `json:"status"`
@@ -92,6 +147,136 @@ type Collection struct {
 	Type     int    `json:"type"`
 	policy   string `json:"-"`
 	revision bool   `json:"-"`
+	// refresh and retry when collection is not found
+	autoRefresh bool
+}
+
+// Refresh reloads collection id, name and type from server, using id if known, so renamed collections are found.
+// Recreated collections get a new id, so they are looked up by name if id is not found.
+func (col *Collection) Refresh() error {
+	var c Collection
+	id := col.Id
+	if id == "" {
+		id = col.Name
+	}
+	res, err := col.db.get("collection", id+"/properties", "GET", nil, &c, nil)
+	if err != nil {
+		return err
+	}
+	if res.Status() == 404 && id != col.Name && col.Name != "" {
+		res, err = col.db.get("collection", col.Name+"/properties", "GET", nil, &c, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch res.Status() {
+	case 200:
+		col.Id = c.Id
+		col.Name = c.Name
+		col.Type = c.Type
+		col.Status = c.Status
+		col.System = c.System
+		return nil
+	case 404:
+		return statusError(res)
+	default:
+		return statusError(res)
+	}
+}
+
+// Properties returns collection properties, like key options, schema and computed values
+func (col *Collection) Properties() (*CollectionOptions, error) {
+	var props CollectionOptions
+	var e ArangoError
+	res, err := col.db.get("collection", col.Name+"/properties", "GET", nil, &props, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return &props, nil
+	case 400, 404:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// Properties of an existing collection that can be changed, nil values are kept
+type CollectionProperties struct {
+	WaitForSync *bool
+	Schema      *CollectionSchema
+	// removes schema, Schema is ignored
+	RemoveSchema bool
+	// empty slice removes computed values
+	ComputedValues []ComputedValue
+	CacheEnabled   *bool
+}
+
+// SetProperties changes properties of collection, returning the updated ones
+func (col *Collection) SetProperties(p CollectionProperties) (*CollectionOptions, error) {
+	body := map[string]interface{}{}
+	if p.WaitForSync != nil {
+		body["waitForSync"] = *p.WaitForSync
+	}
+	if p.RemoveSchema {
+		body["schema"] = nil
+	} else if p.Schema != nil {
+		body["schema"] = p.Schema
+	}
+	if p.ComputedValues != nil {
+		body["computedValues"] = p.ComputedValues
+	}
+	if p.CacheEnabled != nil {
+		body["cacheEnabled"] = *p.CacheEnabled
+	}
+
+	var props CollectionOptions
+	var e ArangoError
+	res, err := col.db.send("collection", col.Name+"/properties", "PUT", body, &props, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return &props, nil
+	case 400, 404:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// AutoRefresh sets if document operations should refresh the collection and retry once, when the collection is not found.
+func (col *Collection) AutoRefresh(refresh bool) {
+	col.autoRefresh = refresh
+}
+
+// do executes request, retrying once after refresh if collection was not found and auto refresh is enabled
+func (col *Collection) do(req func() (*nap.Response, error)) (*nap.Response, error) {
+	res, err := req()
+	if err != nil || !col.autoRefresh || !collectionNotFound(res) {
+		return res, err
+	}
+	if col.Refresh() != nil {
+		return res, err
+	}
+	return req()
+}
+
+// collectionNotFound checks if response is a collection not found error
+func collectionNotFound(res *nap.Response) bool {
+	if res == nil || res.Status() != 404 {
+		return false
+	}
+	var e ArangoError
+	json.Unmarshal([]byte(res.RawText()), &e)
+	return e.ErrorNum == 1203
 }
 
 // Load collection
@@ -103,12 +288,7 @@ func (col *Collection) Load() error {
 		return err
 	}
 
-	switch res.Status() {
-	case 400, 404:
-		return errors.New("Invalid collection to load")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 //Count all documents in collection
@@ -127,13 +307,47 @@ func (col *Collection) Count() int64 {
 	}
 }
 
+// TruncateIfCount truncates collection only if it has expected number of documents.
+// Count and truncate run in a single transaction, so documents can't be added in between.
+func (col *Collection) TruncateIfCount(expected int64) error {
+	q := `function(p){
+        var c = require('internal').db._collection(p.col);
+        var count = c.count();
+        if (count !== p.expected) {
+          return { count: count, truncated: false };
+        }
+        c.truncate();
+        return { count: count, truncated: true };
+    }`
+	var r struct {
+		Count     int64 `json:"count"`
+		Truncated bool  `json:"truncated"`
+	}
+	t := NewTransaction(q, []string{col.Name}, nil)
+	t.Params = map[string]interface{}{"col": col.Name, "expected": expected}
+	t.Result = &r
+	err := t.Execute(col.db)
+	if err != nil {
+		return err
+	}
+	if t.Error {
+		return errors.New("Failed to truncate collection")
+	}
+	if !r.Truncated {
+		return errors.New("Collection " + col.Name + " has " + strconv.FormatInt(r.Count, 10) + " documents, expected " + strconv.FormatInt(expected, 10))
+	}
+	return nil
+}
+
 // Save saves doc into collection, doc should have Document Embedded to retrieve error and Key later.
 func (col *Collection) Save(doc interface{}) error {
 	var err error
 	var res *nap.Response
 
 	if col.Type == 2 {
-		res, err = col.db.send("document?collection="+col.Name, "", "POST", doc, &doc, &doc)
+		res, err = col.do(func() (*nap.Response, error) {
+			return col.db.send("document?collection="+col.Name, "", "POST", doc, &doc, &doc)
+		})
 	} else {
 		return errors.New("Trying to save doc into EdgeCollection")
 	}
@@ -147,12 +361,8 @@ func (col *Collection) Save(doc interface{}) error {
 		return nil
 	case 202:
 		return nil
-	case 400:
-		return errors.New("Invalid document json")
-	case 404:
-		return errors.New("Collection does not exist")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -201,7 +411,7 @@ func (col *Collection) Edges(start string, direction string, result interface{})
 	case 200:
 		return nil
 	default:
-		return errors.New("failed to get edges")
+		return statusError(res)
 	}
 }
 
@@ -226,9 +436,13 @@ func (col *Collection) Get(key string, doc interface{}) error {
 	}
 
 	if col.Type == 2 {
-		_, err = col.db.get("document", col.Name+"/"+key, "GET", nil, &doc, &doc)
+		_, err = col.do(func() (*nap.Response, error) {
+			return col.db.get("document", col.Name+"/"+key, "GET", nil, &doc, &doc)
+		})
 	} else {
-		_, err = col.db.get("edge", col.Name+"/"+key, "GET", nil, &doc, &doc)
+		_, err = col.do(func() (*nap.Response, error) {
+			return col.db.get("edge", col.Name+"/"+key, "GET", nil, &doc, &doc)
+		})
 	}
 
 	if err != nil {
@@ -238,6 +452,109 @@ func (col *Collection) Get(key string, doc interface{}) error {
 	return nil
 }
 
+// GetDirty gets document allowing the read from a follower, returns true if document could be stale.
+func (col *Collection) GetDirty(key string, doc interface{}) (bool, error) {
+	if key == "" {
+		return false, errors.New("Key must not be empty")
+	}
+
+	header := http.Header{}
+	header.Set("x-arango-allow-dirty-read", "true")
+	res, err := col.db.request("document", col.Name+"/"+key, "GET", header, nil, &doc, &doc)
+	if err != nil {
+		return false, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return dirtyRead(res), nil
+	case 404:
+		return false, statusError(res)
+	default:
+		return false, statusError(res)
+	}
+}
+
+// GetMany gets documents by keys in one request, docs must be a pointer to slice.
+// Documents not found are returned with Error set.
+func (col *Collection) GetMany(keys []string, docs interface{}) error {
+	_, err := col.getMany(keys, docs, http.Header{})
+	return err
+}
+
+// GetManyDirty is like GetMany, but allows the read from a follower. Returns true if documents could be stale.
+func (col *Collection) GetManyDirty(keys []string, docs interface{}) (bool, error) {
+	header := http.Header{}
+	header.Set("x-arango-allow-dirty-read", "true")
+	return col.getMany(keys, docs, header)
+}
+
+func (col *Collection) getMany(keys []string, docs interface{}, header http.Header) (bool, error) {
+	kind := reflect.ValueOf(docs).Elem().Kind()
+	if kind != reflect.Slice {
+		return false, errors.New("Container must be Slice kind")
+	}
+	if keys == nil {
+		keys = []string{}
+	}
+
+	res, err := col.db.request("document", col.Name+"?onlyget=true", "PUT", header, keys, docs, nil)
+	if err != nil {
+		return false, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return dirtyRead(res), nil
+	case 404:
+		return false, statusError(res)
+	default:
+		return false, statusError(res)
+	}
+}
+
+// GetOrMissing gets documents by keys into result, which must be a pointer to slice, and returns keys not found.
+func (col *Collection) GetOrMissing(keys []string, result interface{}) ([]string, error) {
+	kind := reflect.ValueOf(result).Elem().Kind()
+	if kind != reflect.Slice {
+		return nil, errors.New("Container must be Slice kind")
+	}
+	if keys == nil {
+		keys = []string{}
+	}
+	q := NewQuery(`LET docs = (FOR k IN @keys RETURN { k: k, d: DOCUMENT(@@col, k) })
+        RETURN { found: (FOR x IN docs FILTER x.d != null RETURN x.d), missing: (FOR x IN docs FILTER x.d == null RETURN x.k) }`)
+	q.BindVars["@col"] = col.Name
+	q.BindVars["keys"] = keys
+
+	c, err := col.db.Execute(q)
+	if err != nil {
+		return nil, err
+	}
+	if c.batchLen() != 1 {
+		return nil, errors.New("Invalid query result")
+	}
+
+	var r struct {
+		Found   interface{} `json:"found"`
+		Missing []string    `json:"missing"`
+	}
+	r.Found = result
+	err = c.decodeRow(0, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r.Missing, nil
+}
+
+// dirtyRead checks if response could come from a follower
+func dirtyRead(res *nap.Response) bool {
+	if res.HttpResponse() == nil {
+		return false
+	}
+	return res.HttpResponse().Header.Get("x-arango-potential-dirty-read") == "true"
+}
+
 // Replace document
 func (col *Collection) Replace(key string, doc interface{}) error {
 	var err error
@@ -248,9 +565,13 @@ func (col *Collection) Replace(key string, doc interface{}) error {
 	}
 
 	if col.Type == 2 {
-		res, err = col.db.send("document", col.Name+"/"+key, "PUT", doc, &doc, &doc)
+		res, err = col.do(func() (*nap.Response, error) {
+			return col.db.send("document", col.Name+"/"+key, "PUT", doc, &doc, &doc)
+		})
 	} else {
-		res, err = col.db.send("edge", col.Name+"/"+key, "PUT", doc, &doc, &doc)
+		res, err = col.do(func() (*nap.Response, error) {
+			return col.db.send("edge", col.Name+"/"+key, "PUT", doc, &doc, &doc)
+		})
 	}
 
 	if err != nil {
@@ -262,12 +583,48 @@ func (col *Collection) Replace(key string, doc interface{}) error {
 		return nil
 	case 202:
 		return nil
-	case 400:
-		return errors.New("Invalid json")
-	case 404:
-		return errors.New("Collection or document was not found")
 	default:
+		return statusError(res)
+	}
+}
+
+// ReplaceClean replaces document like Replace, but null attributes of doc are not stored.
+func (col *Collection) ReplaceClean(key string, doc interface{}) error {
+	var err error
+	var res *nap.Response
+
+	if key == "" {
+		return errors.New("Key must not be empty")
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var clean map[string]interface{}
+	err = json.Unmarshal(b, &clean)
+	if err != nil {
+		return errors.New("Document must be an object")
+	}
+	removeNulls(clean)
+
+	if col.Type == 2 {
+		res, err = col.db.send("document", col.Name+"/"+key, "PUT", clean, &doc, &doc)
+	} else {
+		res, err = col.db.send("edge", col.Name+"/"+key, "PUT", clean, &doc, &doc)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 201:
+		return nil
+	case 202:
 		return nil
+	default:
+		return statusError(res)
 	}
 }
 
@@ -280,9 +637,13 @@ func (col *Collection) Patch(key string, doc interface{}) error {
 	}
 
 	if col.Type == 2 {
-		res, err = col.db.send("document", col.Name+"/"+key, "PATCH", doc, &doc, &doc)
+		res, err = col.do(func() (*nap.Response, error) {
+			return col.db.send("document", col.Name+"/"+key, "PATCH", doc, &doc, &doc)
+		})
 	} else {
-		res, err = col.db.send("edge", col.Name+"/"+key+"?rev=", "PATCH", doc, &doc, &doc)
+		res, err = col.do(func() (*nap.Response, error) {
+			return col.db.send("edge", col.Name+"/"+key+"?rev=", "PATCH", doc, &doc, &doc)
+		})
 	}
 
 	if err != nil {
@@ -294,12 +655,38 @@ func (col *Collection) Patch(key string, doc interface{}) error {
 		return nil
 	case 202:
 		return nil
-	case 400:
-		return errors.New("Body does not contain a valid JSON representation of a document.")
+	default:
+		return statusError(res)
+	}
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to document: null values remove attributes and objects are merged recursively,
+// other values (arrays included) replace the stored ones. It's sent as a PATCH with keepNull=false and mergeObjects=true.
+// Returns updated document metadata.
+func (col *Collection) MergePatch(key string, patch json.RawMessage) (*Document, error) {
+	if key == "" {
+		return nil, errors.New("Key must not be empty")
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(patch, &obj); err != nil || obj == nil {
+		return nil, errors.New("Merge patch must be a JSON object")
+	}
+
+	var doc Document
+	res, err := col.db.send("document", col.Name+"/"+key+"?keepNull=false&mergeObjects=true", "PATCH", patch, &doc, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 201, 202:
+		return &doc, nil
 	case 404:
-		return errors.New("Collection or document was not found")
+		return nil, ErrDocumentNotFound
+	case 412:
+		return nil, statusError(res)
 	default:
-		return nil
+		return nil, statusError(res)
 	}
 }
 
@@ -312,9 +699,13 @@ func (col *Collection) Delete(key string) error {
 	}
 
 	if col.Type == 2 {
-		res, err = col.db.get("document", col.Name+"/"+key, "DELETE", nil, nil, nil)
+		res, err = col.do(func() (*nap.Response, error) {
+			return col.db.get("document", col.Name+"/"+key, "DELETE", nil, nil, nil)
+		})
 	} else {
-		res, err = col.db.get("edge", col.Name+"/"+key, "DELETE", nil, nil, nil)
+		res, err = col.do(func() (*nap.Response, error) {
+			return col.db.get("edge", col.Name+"/"+key, "DELETE", nil, nil, nil)
+		})
 	}
 	if err != nil {
 		return err
@@ -329,6 +720,36 @@ func (col *Collection) Delete(key string) error {
 	}
 }
 
+// DeleteRev deletes document only if rev is its current revision, so it's retried by RetryPolicy
+func (col *Collection) DeleteRev(key string, rev string) error {
+	if key == "" || rev == "" {
+		return errors.New("Key and rev must not be empty")
+	}
+	resource := "edge"
+	if col.Type == 2 {
+		resource = "document"
+	}
+	header := http.Header{}
+	header.Set("If-Match", `"`+rev+`"`)
+	res, err := col.do(func() (*nap.Response, error) {
+		return col.db.request(resource, col.Name+"/"+key, "DELETE", header, nil, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200, 202:
+		return nil
+	case 404:
+		return ErrDocumentNotFound
+	case 412:
+		return ErrRevMismatch
+	default:
+		return statusError(res)
+	}
+}
+
 // Get list of collections from any database
 func Collections(db *Database) error {
 	var err error
@@ -413,6 +834,11 @@ func (c *Collection) All(skip, limit int) (*Cursor, error) {
 	if skip < 0 || limit < 0 {
 		return nil, errors.New("Invalid skip or limit")
 	}
+	if len(c.db.sess.projection) > 0 {
+		q := c.simpleQuery(nil, skip, limit)
+		q.Projection = c.db.sess.projection
+		return c.db.Execute(q)
+	}
 	query := map[string]interface{}{"collection": c.Name, "skip": skip, "limit": limit}
 	res, err := c.db.send("simple", "all", "PUT", query, &cur, &cur)
 
@@ -421,18 +847,42 @@ func (c *Collection) All(skip, limit int) (*Cursor, error) {
 	}
 
 	if res.Status() == 201 {
+		cur.db = c.db
 		return &cur, nil
 	} else {
 		return nil, errors.New("Failed to execute query")
 	}
 }
 
+// simpleQuery returns AQL equivalent to all and by-example simple queries
+func (c *Collection) simpleQuery(example interface{}, skip, limit int) *Query {
+	aql := "FOR doc IN @@col"
+	q := NewQuery("")
+	q.BindVars["@col"] = c.Name
+	if example != nil {
+		aql += " FILTER MATCHES(doc, @example)"
+		q.BindVars["example"] = example
+	}
+	if limit > 0 {
+		aql += " LIMIT @skip, @limit"
+		q.BindVars["skip"] = skip
+		q.BindVars["limit"] = limit
+	}
+	q.Aql = aql + " RETURN doc"
+	return q
+}
+
 //Simple query by example
 func (c *Collection) Example(doc interface{}, skip, limit int) (*Cursor, error) {
 	var cur Cursor
 	if skip < 0 || limit < 0 {
 		return nil, errors.New("Invalid skip or limit")
 	}
+	if len(c.db.sess.projection) > 0 {
+		q := c.simpleQuery(doc, skip, limit)
+		q.Projection = c.db.sess.projection
+		return c.db.Execute(q)
+	}
 	query := map[string]interface{}{"collection": c.Name, "example": doc, "skip": skip, "limit": limit}
 	res, err := c.db.send("simple", "by-example", "PUT", query, &cur, &cur)
 
@@ -441,6 +891,7 @@ func (c *Collection) Example(doc interface{}, skip, limit int) (*Cursor, error)
 	}
 
 	if res.Status() == 201 {
+		cur.db = c.db
 		return &cur, nil
 	} else {
 		return nil, errors.New("Failed to execute query")
@@ -483,6 +934,7 @@ func (c *Collection) ConditionSkipList(condition string, skip int, limit int, in
 	}
 
 	if res.Status() == 201 {
+		cur.db = c.db
 		return &cur, nil
 	} else {
 		return nil, errors.New("Failed to execute query")
@@ -506,6 +958,7 @@ func (c *Collection) ConditionBitArray(condition string, skip int, limit int, in
 	}
 
 	if res.Status() == 201 {
+		cur.db = c.db
 		return &cur, nil
 	} else {
 		return nil, errors.New("Failed to execute query")
@@ -530,6 +983,221 @@ func (c *Collection) Any(doc interface{}) error {
 	}
 }
 
+// InsertMany inserts docs using a single AQL query, documents failing to insert (duplicated keys, etc) are ignored.
+// Returns query stats with the number of writes executed and ignored.
+func (c *Collection) InsertMany(docs interface{}) (*Stats, error) {
+	if docs == nil {
+		return nil, errors.New("Invalid documents to insert")
+	}
+	q := NewQuery("FOR d IN @docs INSERT d INTO @@col OPTIONS { ignoreErrors: true }")
+	q.BindVars["docs"] = docs
+	q.BindVars["@col"] = c.Name
+
+	cur, err := c.db.Execute(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cur.Data.Stats, nil
+}
+
+// DeleteWhere removes documents matching AQL filter expression in server, doc is the document variable:
+//  DeleteWhere("doc.expires < @now", map[string]interface{}{"now": time.Now().Unix()})
+// Returns number of removed documents.
+func (c *Collection) DeleteWhere(filter string, bindVars map[string]interface{}) (int64, error) {
+	return c.DeleteWhereCommit(filter, bindVars, 0)
+}
+
+// DeleteWhereCommit is like DeleteWhere, committing every commitCount removals so big deletions don't
+// exceed transaction size limits. Removals are not rolled back if the query fails.
+func (c *Collection) DeleteWhereCommit(filter string, bindVars map[string]interface{}, commitCount int) (int64, error) {
+	if filter == "" {
+		return 0, errors.New("Filter must not be empty")
+	}
+	if _, ok := bindVars["@col"]; ok {
+		return 0, errors.New("Bind parameter @col is reserved")
+	}
+	q := NewQuery("FOR doc IN @@col FILTER " + filter + " REMOVE doc IN @@col")
+	for k, v := range bindVars {
+		q.BindVars[k] = v
+	}
+	q.BindVars["@col"] = c.Name
+	if commitCount > 0 {
+		q.Options["intermediateCommitCount"] = commitCount
+	}
+
+	cur, err := c.db.Execute(q)
+	if err != nil {
+		return 0, err
+	}
+	return int64(cur.Data.Stats.WritesExecuted), nil
+}
+
+// Name of the norm analyzer used by FindCI, lowercasing and removing accents
+const CIAnalyzer = "norm_ci"
+
+// CIField returns the name of the computed attribute searched by FindCI for field
+func CIField(field string) string {
+	return strings.Replace(field, ".", "_", -1) + "_ci"
+}
+
+// EnsureCI prepares field to be searched with FindCI: it creates the CIAnalyzer analyzer, a computed
+// value CIField(field) holding the normalized field and a sparse persistent index on it.
+// Computed values are only set on write, documents stored before must be updated to be found.
+func (c *Collection) EnsureCI(field string) error {
+	if field == "" {
+		return errors.New("Invalid field")
+	}
+	analyzer := map[string]interface{}{
+		"name":       CIAnalyzer,
+		"type":       "norm",
+		"properties": map[string]interface{}{"locale": "en", "accent": false, "case": "lower"},
+		"features":   []string{},
+	}
+	var e ArangoError
+	res, err := c.db.send("analyzer", "", "POST", analyzer, nil, &e)
+	if err != nil {
+		return err
+	}
+	if err = statusError(res); err != nil {
+		return err
+	}
+
+	props, err := c.Properties()
+	if err != nil {
+		return err
+	}
+	path := "@doc"
+	for _, p := range strings.Split(field, ".") {
+		path += ".`" + p + "`"
+	}
+	name := CIField(field)
+	values := []ComputedValue{{
+		Name:       name,
+		Expression: "RETURN TOKENS(" + path + ", \"" + CIAnalyzer + "\")[0]",
+		Overwrite:  true,
+	}}
+	for _, v := range props.ComputedValues {
+		if v.Name != name {
+			values = append(values, v)
+		}
+	}
+	if _, err = c.SetProperties(CollectionProperties{ComputedValues: values}); err != nil {
+		return err
+	}
+
+	_, err = c.CreateIndex(PersistentIndex{Fields: []string{name}, Sparse: true})
+	return err
+}
+
+// FindCI finds documents where field is equal to value ignoring case and accents, result must be a pointer to slice.
+// It searches the computed attribute CIField(field) using its index, EnsureCI must be called once before.
+func (c *Collection) FindCI(field, value string, result interface{}) error {
+	if field == "" {
+		return errors.New("Invalid field")
+	}
+	q := NewQuery("RETURN (FOR doc IN @@col FILTER doc.@field == TOKENS(@value, @analyzer)[0] RETURN doc)")
+	q.BindVars["@col"] = c.Name
+	q.BindVars["field"] = CIField(field)
+	q.BindVars["value"] = value
+	q.BindVars["analyzer"] = CIAnalyzer
+
+	return c.db.executeList(q, result)
+}
+
+// ExportJSONL writes all documents of collection into w, one JSON document per line. Returns number of documents written.
+func (c *Collection) ExportJSONL(w io.Writer, batchSize int) (int64, error) {
+	return c.ExportJSONLContext(c.db.context(), w, batchSize)
+}
+
+// ExportJSONLContext is like ExportJSONL, stops if ctx is done, also while a batch is fetched.
+// Documents are written as sent by the server using a streaming cursor, without decoding them.
+func (c *Collection) ExportJSONLContext(ctx context.Context, w io.Writer, batchSize int) (int64, error) {
+	q := NewQuery("FOR doc IN @@col RETURN doc")
+	q.BindVars["@col"] = c.Name
+	q.Batch = batchSize
+	q.Options["stream"] = true
+
+	cur, err := c.db.Execute(q)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	var buf bytes.Buffer
+	for {
+		if err = ctx.Err(); err != nil {
+			cur.Delete()
+			return n, err
+		}
+		raw, err := cur.RawBatch()
+		if err != nil {
+			return n, err
+		}
+		var docs []json.RawMessage
+		if err = json.Unmarshal(raw, &docs); err != nil {
+			cur.Delete()
+			return n, err
+		}
+		for _, d := range docs {
+			buf.Reset()
+			if err = json.Compact(&buf, d); err != nil {
+				cur.Delete()
+				return n, err
+			}
+			buf.WriteByte('\n')
+			if _, err = w.Write(buf.Bytes()); err != nil {
+				cur.Delete()
+				return n, err
+			}
+			n++
+		}
+		if !cur.HasMore() {
+			return n, nil
+		}
+		if err = cur.NextBatchCtx(ctx); err != nil {
+			cur.Delete()
+			return n, err
+		}
+	}
+}
+
+// DateFormat defines how dates are stored in documents
+type DateFormat int
+
+const (
+	// UTC ISO 8601 string with milliseconds, as DATE_ISO8601()
+	DateISO DateFormat = iota
+	// Milliseconds since epoch, as DATE_NOW()
+	DateEpochMillis
+)
+
+// encodeDate returns t encoded as stored in documents
+func encodeDate(t time.Time, f DateFormat) interface{} {
+	switch f {
+	case DateEpochMillis:
+		return t.UnixNano() / int64(time.Millisecond)
+	default:
+		return t.UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+}
+
+// WhereBetweenDates returns documents with field date between from (included) and to (excluded) into result,
+// which must be a pointer to slice. Dates are encoded using session date format, see Session.SetDateFormat.
+func (c *Collection) WhereBetweenDates(field string, from, to time.Time, result interface{}) error {
+	if field == "" {
+		return errors.New("Invalid field")
+	}
+	f := c.db.sess.dateFormat
+	q := NewQuery("RETURN (FOR doc IN @@col FILTER doc.@field >= @from && doc.@field < @to RETURN doc)")
+	q.BindVars["@col"] = c.Name
+	q.BindVars["field"] = field
+	q.BindVars["from"] = encodeDate(from, f)
+	q.BindVars["to"] = encodeDate(to, f)
+
+	return c.db.executeList(q, result)
+}
+
 //Get all indexs
 func (c *Collection) Indexes() (map[string]Index, error) {
 	var indexes Indexes
@@ -541,22 +1209,35 @@ func (c *Collection) Indexes() (map[string]Index, error) {
 	return indexes.IndexMap, err
 }
 
-// Delete Index
-func (c *Collection) DeleteIndex(id string) error {
-	if id == "" {
-		return errors.New("Invalid id")
+// IndexSelectivity returns selectivity estimate of collection indexes by index id.
+// Indexes without estimate (geo, fulltext...) are not included.
+func (c *Collection) IndexSelectivity() (map[string]float64, error) {
+	var indexes struct {
+		Indexes []struct {
+			Id          string   `json:"id"`
+			Selectivity *float64 `json:"selectivityEstimate"`
+		} `json:"indexes"`
 	}
-
-	res, err := c.db.get("index", "id", "DELETE", nil, nil, nil)
+	res, err := c.db.get("index?collection="+c.Name, "", "GET", nil, &indexes, &indexes)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if res.Status() != 200 {
+		return nil, errors.New("Failed to get indexes")
 	}
 
-	if res.Status() == 404 {
-		return errors.New("Index does not exist")
+	sel := make(map[string]float64)
+	for _, i := range indexes.Indexes {
+		if i.Selectivity != nil {
+			sel[i.Id] = *i.Selectivity
+		}
 	}
+	return sel, nil
+}
 
-	return nil
+// Delete Index
+func (c *Collection) DeleteIndex(id string) error {
+	return c.DropIndex(id)
 }
 
 //Create cap constraint
@@ -576,14 +1257,7 @@ func (c *Collection) SetCap(size int64, bysize int64) error {
 		return err
 	}
 
-	switch res.Status() {
-	case 400:
-		return errors.New("Invalid size or byte-size values")
-	case 404:
-		return errors.New("Collection does not exist")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 func (c *Collection) CreateHash(unique bool, fields ...string) error {
@@ -594,14 +1268,7 @@ func (c *Collection) CreateHash(unique bool, fields ...string) error {
 		return err
 	}
 
-	switch res.Status() {
-	case 400:
-		return errors.New("There are documents violating the uniqueness")
-	case 404:
-		return errors.New("Collection does not exist")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 func (c *Collection) CreateSkipList(unique bool, fields ...string) error {
@@ -612,14 +1279,7 @@ func (c *Collection) CreateSkipList(unique bool, fields ...string) error {
 		return err
 	}
 
-	switch res.Status() {
-	case 400:
-		return errors.New("There are documents violating the uniqueness")
-	case 404:
-		return errors.New("Collection does not exist")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 func (c *Collection) CreateGeoIndex(unique bool, geojson bool, fields ...string) error {
@@ -630,16 +1290,12 @@ func (c *Collection) CreateGeoIndex(unique bool, geojson bool, fields ...string)
 		return err
 	}
 
-	switch res.Status() {
-	case 400:
-		return errors.New("There are documents violating the uniqueness")
-	case 404:
-		return errors.New("Collection does not exist")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
+// Near runs the near simple query.
+//
+// Deprecated: simple queries were removed from server, use Nearest.
 func (c *Collection) Near(lat float64, lon float64, distance bool, geo string, skip, limit int) (*Cursor, error) {
 	var cur Cursor
 	if skip < 0 || limit < 0 {
@@ -663,12 +1319,16 @@ func (c *Collection) Near(lat float64, lon float64, distance bool, geo string, s
 	}
 
 	if res.Status() == 201 {
+		cur.db = c.db
 		return &cur, nil
 	} else {
 		return nil, errors.New("Failed to execute query")
 	}
 }
 
+// WithIn runs the within simple query.
+//
+// Deprecated: simple queries were removed from server, use WithinRadius.
 func (c *Collection) WithIn(radius float64, lat float64, lon float64, distance bool, geo string, skip, limit int) (*Cursor, error) {
 	var cur Cursor
 	if skip < 0 || limit < 0 {
@@ -693,6 +1353,7 @@ func (c *Collection) WithIn(radius float64, lat float64, lon float64, distance b
 	}
 
 	if res.Status() == 201 {
+		cur.db = c.db
 		return &cur, nil
 	} else {
 		return nil, errors.New("Failed to execute query")
@@ -707,12 +1368,7 @@ func (c *Collection) CreateFullText(min int, fields ...string) error {
 		return err
 	}
 
-	switch res.Status() {
-	case 404:
-		return errors.New("Collection does not exist")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 func (c *Collection) FullText(q string, atr string, skip, limit int) (*Cursor, error) {
@@ -729,6 +1385,7 @@ func (c *Collection) FullText(q string, atr string, skip, limit int) (*Cursor, e
 	}
 
 	if res.Status() == 201 {
+		cur.db = c.db
 		return &cur, nil
 	} else {
 		return nil, errors.New("Failed to execute query")
@@ -747,4 +1404,81 @@ type Index struct {
 	MinLength int      `json:"minLength"`
 	Fields    []string `json:"fields"`
 	Size      int64    `json:"size"`
+	// Not present in all index types
+	Selectivity float64 `json:"selectivityEstimate"`
+	Name        string  `json:"name"`
+	Sparse      bool    `json:"sparse"`
+	GeoJson     bool    `json:"geoJson"`
+	ExpireAfter int     `json:"expireAfter"`
+	// false if an equal index already existed
+	IsNewlyCreated bool `json:"isNewlyCreated"`
+}
+
+// ValidateExisting returns the keys of documents not matching schema rule, documents are validated in server
+// with SCHEMA_VALIDATE reading batchSize documents at a time. Useful before making validation level stricter.
+func (c *Collection) ValidateExisting(schema CollectionSchema, batchSize int) ([]string, error) {
+	if schema.Rule == nil {
+		return nil, errors.New("Schema rule must not be empty")
+	}
+	q := NewQuery("FOR doc IN @@col FILTER !SCHEMA_VALIDATE(doc, @schema).valid RETURN doc._key")
+	q.BindVars["@col"] = c.Name
+	q.BindVars["schema"] = schema
+	q.Batch = batchSize
+	q.Options["stream"] = true
+
+	cur, err := c.db.Execute(q)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	for {
+		var batch []string
+		if err = cur.decodeBatch(&batch); err != nil {
+			cur.Delete()
+			return keys, err
+		}
+		keys = append(keys, batch...)
+		if !cur.HasMore() {
+			return keys, nil
+		}
+		if err = cur.NextBatch(); err != nil {
+			return keys, err
+		}
+	}
+}
+
+// KeyRevs returns _rev of every document by _key, without reading document bodies.
+// Documents are read with a streaming cursor, batchSize documents at a time.
+func (c *Collection) KeyRevs(batchSize int) (map[string]string, error) {
+	q := NewQuery("FOR doc IN @@col RETURN { k: doc._key, r: doc._rev }")
+	q.BindVars["@col"] = c.Name
+	q.Batch = batchSize
+	q.Options["stream"] = true
+
+	cur, err := c.db.Execute(q)
+	if err != nil {
+		return nil, err
+	}
+
+	revs := make(map[string]string)
+	for {
+		var batch []struct {
+			Key string `json:"k"`
+			Rev string `json:"r"`
+		}
+		if err = cur.decodeBatch(&batch); err != nil {
+			cur.Delete()
+			return revs, err
+		}
+		for _, kr := range batch {
+			revs[kr.Key] = kr.Rev
+		}
+		if !cur.HasMore() {
+			return revs, nil
+		}
+		if err = cur.NextBatch(); err != nil {
+			return revs, err
+		}
+	}
 }
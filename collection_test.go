@@ -0,0 +1,68 @@
+package arango
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportJSONLContext(t *testing.T) {
+	pt := &prefetchTransport{started: make(chan struct{}, 1), created: `{"id":"1","result":[{"a": 1}],"hasMore":true}`}
+	col := &Collection{db: testDB(pt), Name: "users"}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-pt.started
+		cancel()
+	}()
+	var buf bytes.Buffer
+	n, err := col.ExportJSONLContext(ctx, &buf, 1)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, "{\"a\":1}\n", buf.String())
+	// cursor is deleted when the batch request is cancelled
+	assert.Equal(t, 1, pt.deletes())
+}
+
+func TestEnsureCI(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{201, `{"name":"shop::norm_ci","type":"norm"}`},
+		{200, `{"name":"users","computedValues":[{"name":"other","expression":"RETURN 1"},{"name":"email_ci","expression":"RETURN 2"}]}`},
+		{200, `{"name":"users"}`},
+		{201, `{"id":"users/7","type":"persistent"}`},
+	}}
+	col := &Collection{db: testDB(st), Name: "users"}
+	assert.Nil(t, col.EnsureCI("email"))
+	assert.Equal(t, 4, len(st.reqs))
+	assert.Equal(t, "/_db/shop/_api/analyzer", st.reqs[0].URL.Path)
+
+	var props struct {
+		ComputedValues []ComputedValue `json:"computedValues"`
+	}
+	assert.Nil(t, json.NewDecoder(st.reqs[2].Body).Decode(&props))
+	assert.Equal(t, 2, len(props.ComputedValues))
+	assert.Equal(t, "email_ci", props.ComputedValues[0].Name)
+	assert.Equal(t, "RETURN TOKENS(@doc.`email`, \"norm_ci\")[0]", props.ComputedValues[0].Expression)
+	assert.Equal(t, "other", props.ComputedValues[1].Name)
+
+	var idx map[string]interface{}
+	assert.Nil(t, json.NewDecoder(st.reqs[3].Body).Decode(&idx))
+	assert.Equal(t, []interface{}{"email_ci"}, idx["fields"])
+	assert.Equal(t, true, idx["sparse"])
+	assert.Equal(t, "address_email_ci", CIField("address.email"))
+}
+
+func TestCollectionRefresh(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{404, `{"error":true,"code":404,"errorNum":1203,"errorMessage":"collection not found"}`},
+		{200, `{"id":"99","name":"users","type":2,"status":3}`},
+	}}
+	col := &Collection{db: testDB(st), Id: "12", Name: "users"}
+	assert.Nil(t, col.Refresh())
+	assert.Equal(t, "99", col.Id)
+	assert.Equal(t, 2, col.Type)
+	assert.Equal(t, "/_db/shop/_api/collection/12/properties", st.reqs[0].URL.Path)
+	assert.Equal(t, "/_db/shop/_api/collection/users/properties", st.reqs[1].URL.Path)
+}
@@ -1,29 +1,131 @@
 package arango
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
+
+	nap "github.com/diegogub/napping"
 )
 
 type Cursor struct {
 	db *Database `json:"-"`
 	Id string    `json:"id"`
 
-	Index  int           `json:"-"`
+	Index int `json:"-"`
+	// Deprecated: Result is filled on demand by Values, batches are decoded by fetch methods
 	Result []interface{} `json:"result"`
 	More   bool          `json:"hasMore"`
 	Amount int           `json:"count"`
 	Data   Extra         `json:"extra"`
 	Cached bool          `json:"cached"`
 
-	Err    bool   `json:"error"`
-	ErrMsg string `json:"errorMessage"`
-	Code   int    `json:"code"`
-	max    int
-	Time   time.Duration `json:"time"`
+	// Deprecated: Err and ErrMsg are replaced by errors returned as *ArangoError
+	Err      bool   `json:"error"`
+	ErrMsg   string `json:"errorMessage"`
+	Code     int    `json:"code"`
+	ErrorNum int    `json:"errorNum"`
+	max      int
+	Time     time.Duration `json:"time"`
+
+	// query that created the cursor
+	query *Query
+	// raw JSON array of the current batch and its results, decoded on demand
+	raw  json.RawMessage
+	rows []json.RawMessage
+	// current batch was returned by FetchBatch
+	batchRead bool
+	// time spent in http requests and json decoding
+	netTime time.Duration
+	decTime time.Duration
+
+	// fetch and iteration methods hold mu
+	mu sync.Mutex
+	// request next batch in background, pending receives it and cancelPrefetch aborts it
+	prefetch       bool
+	pending        chan prefetchedBatch
+	cancelPrefetch context.CancelFunc
+}
+
+// next batch fetched in background
+type prefetchedBatch struct {
+	next *Cursor
+	err  error
+}
+
+// UnmarshalJSON decodes the server response, keeping the raw result array of the batch.
+func (c *Cursor) UnmarshalJSON(b []byte) error {
+	t0 := time.Now()
+	defer func() { c.decTime += time.Since(t0) }()
+
+	type cursor Cursor
+	var aux struct {
+		*cursor
+		Raw json.RawMessage `json:"result"`
+	}
+	aux.cursor = (*cursor)(c)
+	err := json.Unmarshal(b, &aux)
+	if err != nil {
+		return err
+	}
+	// error responses don't carry results, keep current batch
+	if aux.Raw == nil {
+		return nil
+	}
+	// results are split, not decoded, until they are fetched
+	c.raw = aux.Raw
+	c.rows = nil
+	c.batchRead = false
+	c.Result = nil
+	err = json.Unmarshal(aux.Raw, &c.rows)
+	c.max = len(c.rows) - 1
+	return err
+}
+
+// Values returns results of current batch decoded as generic values, filling Result
+func (c *Cursor) Values() ([]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Result == nil && c.raw != nil {
+		if err := c.decodeBatch(&c.Result); err != nil {
+			return nil, err
+		}
+	}
+	return c.Result, nil
+}
+
+// batchLen returns number of results in current batch
+func (c *Cursor) batchLen() int {
+	if c.raw == nil {
+		return len(c.Result)
+	}
+	rows, _ := c.batchRows()
+	return len(rows)
+}
+
+// request sends cursor request, tracking network time apart from response decoding time
+func (c *Cursor) request(ctx context.Context, id string, method string, payload interface{}) (*nap.Response, error) {
+	dec := c.decTime
+	t0 := time.Now()
+	res, err := c.db.sendCtx(ctx, "cursor", id, method, payload, c, c)
+	c.netTime += time.Since(t0) - (c.decTime - dec)
+	return res, err
+}
+
+// decode decodes result value v into r
+func (c *Cursor) decode(v interface{}, r interface{}) error {
+	t0 := time.Now()
+	defer func() { c.decTime += time.Since(t0) }()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, r)
 }
 
 func NewCursor(db *Database) *Cursor {
@@ -35,18 +137,27 @@ func NewCursor(db *Database) *Cursor {
 	return &c
 }
 
-// Delete cursor in server and free RAM
+// Delete cursor in server and free RAM, a prefetch in flight is cancelled
 func (c *Cursor) Delete() (bool, error) {
-	if c.Id == "" {
+	c.mu.Lock()
+	c.stopPrefetch()
+	id := c.Id
+	c.mu.Unlock()
+	return c.delete(id)
+}
+
+// delete deletes cursor id in server, response is not decoded into cursor so it doesn't need the lock
+func (c *Cursor) delete(id string) (bool, error) {
+	if id == "" {
 		return false, nil
 	}
-	res, err := c.db.send("cursor", c.Id, "DELETE", nil, c, c)
+	res, err := c.db.send("cursor", id, "DELETE", nil, nil, nil)
 	if err != nil {
 		return false, err
 	}
 
 	switch res.Status() {
-	case 202:
+	case 200, 202:
 		return true, nil
 	case 404:
 		return false, nil
@@ -56,59 +167,292 @@ func (c *Cursor) Delete() (bool, error) {
 
 }
 
-func (c *Cursor) FetchBatch(r interface{}) error {
-	kind := reflect.ValueOf(r).Elem().Kind()
-	if kind != reflect.Slice && kind != reflect.Array {
-		return errors.New("Container must be Slice of array kind")
+// RawBatch returns the current batch as the raw JSON array sent by the server, without decoding it.
+// Use HasMore and NextBatch to move over the rest of the batches.
+func (c *Cursor) RawBatch() ([]byte, error) {
+	if c.raw == nil {
+		if c.Result != nil {
+			return json.Marshal(c.Result)
+		}
+		return []byte("[]"), nil
 	}
-	b, err := json.Marshal(c.Result)
+	return c.raw, nil
+}
+
+// NextBatch fetches next batch from server, replacing current one.
+func (c *Cursor) NextBatch() error {
+	return c.NextBatchCtx(c.db.context())
+}
+
+// NextBatchCtx is like NextBatch, the request is cancelled when ctx is done returning ctx.Err().
+func (c *Cursor) NextBatchCtx(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nextBatch(ctx)
+}
+
+// SetPrefetch makes FetchNext request the next batch in background while the current one is read,
+// hiding network latency. Cursor must be deleted if it's not consumed.
+func (c *Cursor) SetPrefetch(prefetch bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prefetch = prefetch
+}
+
+// nextBatch fetches next batch, or takes the prefetched one. Current batch and index are kept if request fails
+func (c *Cursor) nextBatch(ctx context.Context) error {
+	if !c.More {
+		return errors.New("Cursor has no more batches")
+	}
+	if c.pending != nil {
+		select {
+		case b := <-c.pending:
+			c.pending, c.cancelPrefetch = nil, nil
+			if b.err != nil {
+				return b.err
+			}
+			c.setBatch(b.next)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	res, err := c.request(ctx, c.Id, "PUT", nil)
 	if err != nil {
 		return err
 	}
-	err = json.Unmarshal(b, r)
+	if res.Status() != 200 {
+		return c.batchError(res)
+	}
+	c.Index = 0
+	return nil
+}
+
+// batchError returns error of a failed batch request
+func (c *Cursor) batchError(res *nap.Response) error {
+	msg := c.ErrMsg
+	if msg == "" {
+		msg = "Cursor batch request returned status code of " + strconv.Itoa(res.Status())
+	}
+	return &ArangoError{Code: res.Status(), ErrorNum: c.ErrorNum, Message: msg, Header: responseHeader(res)}
+}
+
+// startPrefetch requests next batch in background into another cursor, the request is cancelled
+// with ctx or by stopPrefetch. Must hold lock.
+func (c *Cursor) startPrefetch(ctx context.Context) {
+	ch := make(chan prefetchedBatch, 1)
+	ctx, cancel := context.WithCancel(ctx)
+	c.pending, c.cancelPrefetch = ch, cancel
+	db, id := c.db, c.Id
+	go func() {
+		defer cancel()
+		n := &Cursor{db: db, Id: id}
+		res, err := n.request(ctx, id, "PUT", nil)
+		if err != nil {
+			err = ctxErr(ctx, err)
+		} else if res.Status() != 200 {
+			err = n.batchError(res)
+		}
+		ch <- prefetchedBatch{next: n, err: err}
+	}()
+}
+
+// stopPrefetch cancels the batch request in flight without waiting for it, must hold lock
+func (c *Cursor) stopPrefetch() {
+	if c.pending != nil {
+		c.cancelPrefetch()
+		c.pending, c.cancelPrefetch = nil, nil
+	}
+}
+
+// setBatch replaces current batch with the one of n
+func (c *Cursor) setBatch(n *Cursor) {
+	c.raw = n.raw
+	c.rows = n.rows
+	c.Result = n.Result
+	c.max = n.max
+	c.More = n.More
+	c.Index = 0
+	c.batchRead = false
+	c.netTime += n.netTime
+	c.decTime += n.decTime
+}
+
+// decodeBatch decodes current batch into r, from raw batch if available
+func (c *Cursor) decodeBatch(r interface{}) error {
+	if c.raw == nil {
+		return c.decode(c.Result, r)
+	}
+	t0 := time.Now()
+	defer func() { c.decTime += time.Since(t0) }()
+	return json.Unmarshal(c.raw, r)
+}
+
+// decodeRow decodes result i of current batch into r from the raw batch, so numbers don't lose precision
+func (c *Cursor) decodeRow(i int, r interface{}) error {
+	if c.raw == nil {
+		return c.decode(c.Result[i], r)
+	}
+	t0 := time.Now()
+	defer func() { c.decTime += time.Since(t0) }()
+	rows, err := c.batchRows()
 	if err != nil {
 		return err
 	}
+	return json.Unmarshal(rows[i], r)
+}
 
-	// fetch next batch
-	if c.HasMore() {
-		res, err := c.db.send("cursor", c.Id, "PUT", nil, c, c)
-		if res.Status() == 200 {
-			return nil
+// batchRows returns raw results of current batch, splitting raw batch once
+func (c *Cursor) batchRows() ([]json.RawMessage, error) {
+	if c.rows != nil {
+		return c.rows, nil
+	}
+	if c.raw == nil {
+		b, err := json.Marshal(c.Result)
+		if err != nil {
+			return nil, err
 		}
+		c.raw = b
+	}
+	err := json.Unmarshal(c.raw, &c.rows)
+	return c.rows, err
+}
 
+// Iterate calls fn with every remaining result as raw JSON, fetching next batches from server. The cursor only
+// splits batches into results, fn decodes each of them once. Stops returning the first error of fn or of a batch request.
+// fn must not call cursor methods.
+func (c *Cursor) Iterate(fn func(json.RawMessage) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		rows, err := c.batchRows()
 		if err != nil {
 			return err
 		}
+		for c.Index < len(rows) {
+			row := rows[c.Index]
+			c.Index++
+			if err = fn(row); err != nil {
+				return err
+			}
+		}
+		if !c.More {
+			return nil
+		}
+		if err = c.nextBatch(c.db.context()); err != nil {
+			return err
+		}
 	}
+}
 
+// FetchBatchField decodes field of every result in current batch into r, like FetchBatch.
+// Useful when query returns objects wrapping the values, results without field are skipped.
+func (c *Cursor) FetchBatchField(field string, r interface{}) error {
+	kind := reflect.ValueOf(r).Elem().Kind()
+	if kind != reflect.Slice && kind != reflect.Array {
+		return errors.New("Container must be Slice of array kind")
+	}
+	var rows []json.RawMessage
+	if err := c.decodeBatch(&rows); err != nil {
+		return err
+	}
+	values := make([]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(row, &obj) != nil {
+			continue
+		}
+		if v, ok := obj[field]; ok {
+			values = append(values, v)
+		}
+	}
+	return c.decode(values, r)
+}
+
+// FetchBatch decodes one server batch into r on each call, fetching the next one when the current batch was
+// already returned. Call it once, then again while HasMore() is true to get every batch exactly once.
+func (c *Cursor) FetchBatch(r interface{}) error {
+	kind := reflect.ValueOf(r).Elem().Kind()
+	if kind != reflect.Slice && kind != reflect.Array {
+		return errors.New("Container must be Slice of array kind")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.batchRead {
+		if err := c.nextBatch(c.db.context()); err != nil {
+			return err
+		}
+	}
+	err := c.decodeBatch(r)
+	if err != nil {
+		return err
+	}
+	c.batchRead = true
+	c.Index = c.batchLen()
 	return nil
 }
 
+// ErrTooManyResults is returned by FetchAllMax when query returns more results than allowed
+var ErrTooManyResults = errors.New("Query returned too many results")
+
+// FetchAll decodes the rest of results into r, fetching all batches from server. r must be a pointer to slice,
+// results are appended to it. Cursor is deleted from server when it's consumed.
+func (c *Cursor) FetchAll(r interface{}) error {
+	return c.FetchAllMax(r, 0)
+}
+
+// FetchAllMax is like FetchAll, returning ErrTooManyResults and deleting cursor if more than max results
+// would be decoded. No limit if max is 0.
+func (c *Cursor) FetchAllMax(r interface{}, max int) error {
+	v := reflect.ValueOf(r)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return errors.New("Container must be pointer to Slice")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := v.Elem()
+	n := 0
+	for {
+		n += c.batchLen() - c.Index
+		if max > 0 && n > max {
+			c.delete(c.Id)
+			return ErrTooManyResults
+		}
+		batch := reflect.New(out.Type())
+		if err := c.decodeBatch(batch.Interface()); err != nil {
+			return err
+		}
+		rows := batch.Elem()
+		if c.Index > 0 && c.Index <= rows.Len() {
+			rows = rows.Slice(c.Index, rows.Len())
+		}
+		out.Set(reflect.AppendSlice(out, rows))
+		c.Index = c.batchLen()
+		if !c.More {
+			break
+		}
+		if err := c.nextBatch(c.db.context()); err != nil {
+			return err
+		}
+	}
+	_, err := c.delete(c.Id)
+	return err
+}
+
 // FetchOne iterates over cursor, returns false when no more values into batch, fetch next batch if necesary.
 func (c *Cursor) FetchOne(r interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.Index > c.max {
 		if c.More {
 			//fetch rest from server
-			res, err := c.db.send("cursor", c.Id, "PUT", nil, c, c)
-
-			if err != nil {
-				return false
-			}
-
-			if res.Status() == 200 {
-				c.Index = 0
-				return true
-			} else {
-				return false
-			}
+			return c.nextBatch(c.db.context()) == nil
 		} else {
 			// last doc
 			return false
 		}
 	} else {
-		b, err := json.Marshal(c.Result[c.Index])
-		err = json.Unmarshal(b, r)
+		err := c.decodeRow(c.Index, r)
 		c.Index++ // move to next value into result
 		if err != nil {
 			return false
@@ -120,31 +464,43 @@ func (c *Cursor) FetchOne(r interface{}) bool {
 
 // FetchNext is similar to FetchOne.  It is a custom implementation to access an API that exposes a bool if there are more items and an error if there was a parsing issue.
 func (c *Cursor) FetchNext(r interface{}) (bool, error) {
-	if c.Index >= len(c.Result) {
+	return c.FetchNextCtx(c.db.context(), r)
+}
+
+// FetchNextCtx is like FetchNext, next batch request is cancelled when ctx is done returning ctx.Err().
+// With prefetch the background request is started with ctx too, and cancelled with it.
+// The cursor can be used again after a cancelled request, but the server may have already moved to next batch.
+// Cursor can be shared by goroutines calling FetchNext, each result is decoded once.
+func (c *Cursor) FetchNextCtx(ctx context.Context, r interface{}) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fetchNext(ctx, r)
+}
+
+func (c *Cursor) fetchNext(ctx context.Context, r interface{}) (bool, error) {
+	if c.prefetch && c.More && c.pending == nil {
+		c.startPrefetch(ctx)
+	}
+	if c.Index >= c.batchLen() {
 		if c.More {
 			//fetch rest from server
-			res, err := c.db.send("cursor", c.Id, "PUT", nil, c, c)
-
-			if err != nil {
+			if err := ctx.Err(); err != nil {
 				return false, err
 			}
-
-			if res.Status() == 200 {
-				c.Index = 0
-			} else {
-				return false, errors.New("Cursor batch request returned status code of " + strconv.Itoa(res.Status()))
+			if err := c.nextBatch(ctx); err != nil {
+				return false, err
 			}
 		} else {
 			// last doc
 			return false, nil
 		}
 	}
-
-	b, err := json.Marshal(c.Result[c.Index])
-	if err != nil {
-		return false, err
+	// empty batch
+	if c.Index >= c.batchLen() {
+		return c.fetchNext(ctx, r)
 	}
-	err = json.Unmarshal(b, r)
+
+	err := c.decodeRow(c.Index, r)
 	if err != nil {
 		return false, err
 	}
@@ -153,23 +509,26 @@ func (c *Cursor) FetchNext(r interface{}) (bool, error) {
 
 }
 
-// move cursor index by 1
+// Next decodes current result into r and moves cursor index by 1, fetching next batch if necessary.
+// Returns false when cursor is consumed or on errors, use FetchNext to get them.
 func (c *Cursor) Next(r interface{}) bool {
-	if c.Index == c.max {
-		return false
-	} else {
-		c.Index++
-		if c.Index == c.max {
-			return true
-		} else {
-			return false
-		}
-	}
+	ok, err := c.FetchNext(r)
+	return ok && err == nil
 }
 
 type Extra struct {
-	Stats    Stats         `json:"stats"`
-	Warnings []interface{} `json:"warnings"`
+	Stats    Stats     `json:"stats"`
+	Warnings []Warning `json:"warnings"`
+	// seconds spent in each query phase, if profile option was set
+	Profile map[string]float64 `json:"profile"`
+	// executed plan, if ProfileNodes option was set
+	Plan *QueryPlan `json:"plan"`
+}
+
+// Query warning, like document not found in a non strict query
+type Warning struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
 }
 
 type Stats struct {
@@ -180,9 +539,20 @@ type Stats struct {
 	Filtered       int     `json:"filtered"`
 	ExecutionTime  float64 `json:"executionTime"`
 	FullCount      int     `json:"fullCount"`
+	// stats of each plan node, if ProfileNodes option was set
+	Nodes []NodeStats `json:"nodes"`
 }
 
-func (c Cursor) Count() int {
+// Execution stats of a plan node
+type NodeStats struct {
+	Id    int   `json:"id"`
+	Calls int64 `json:"calls"`
+	Items int64 `json:"items"`
+	// seconds
+	Runtime float64 `json:"runtime"`
+}
+
+func (c *Cursor) Count() int {
 	return c.Amount
 }
 
@@ -190,14 +560,61 @@ func (c *Cursor) FullCount() int {
 	return c.Data.Stats.FullCount
 }
 
-func (c Cursor) HasMore() bool {
+// Warnings returns warnings raised by query, empty if there are none
+func (c *Cursor) Warnings() []Warning {
+	if c.Data.Warnings == nil {
+		return []Warning{}
+	}
+	return c.Data.Warnings
+}
+
+// HasWarnings checks if query raised any warning
+func (c *Cursor) HasWarnings() bool {
+	return len(c.Data.Warnings) > 0
+}
+
+// Profile returns seconds spent in each query phase, empty if query was not executed with Profile option
+func (c *Cursor) Profile() map[string]float64 {
+	if c.Data.Profile == nil {
+		return map[string]float64{}
+	}
+	return c.Data.Profile
+}
+
+// NetworkTime returns the time spent on http requests (network and server) by the cursor
+func (c *Cursor) NetworkTime() time.Duration {
+	return c.netTime
+}
+
+// DecodeTime returns the time spent decoding json responses and results by the cursor
+func (c *Cursor) DecodeTime() time.Duration {
+	return c.decTime
+}
+
+func (c *Cursor) HasMore() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.More
 }
 
-func (c Cursor) Error() bool {
+// Deprecated: errors are returned as *ArangoError, see ServerError
+func (c *Cursor) Error() bool {
 	return c.Err
 }
 
-func (c Cursor) ErrCode() int {
+func (c *Cursor) ErrCode() int {
 	return c.Code
 }
+
+// ServerError returns error of last cursor response as *ArangoError, nil if there is none
+func (c *Cursor) ServerError() error {
+	if !c.Err {
+		return nil
+	}
+	return &ArangoError{Code: c.Code, ErrorNum: c.ErrorNum, Message: c.ErrMsg}
+}
+
+// ErrNum returns ArangoDB error number
+func (c *Cursor) ErrNum() int {
+	return c.ErrorNum
+}
@@ -0,0 +1,408 @@
+package arango
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cursor decoded from a server response, without more batches
+func testCursor(t *testing.T, response string) *Cursor {
+	var c Cursor
+	err := json.Unmarshal([]byte(response), &c)
+	assert.Nil(t, err)
+	return &c
+}
+
+type batchRow struct {
+	Name  string                 `json:"name"`
+	Tags  []string               `json:"tags"`
+	Attrs map[string]interface{} `json:"attrs"`
+}
+
+func TestFetchBatch(t *testing.T) {
+	response := `{"result":[{"name":"a","tags":["x","y"],"attrs":{"n":{"m":1}}},{"name":"b","extra":true},{"name":"c","attrs":{}}],"hasMore":false,"count":3}`
+
+	c := testCursor(t, response)
+	var maps []map[string]interface{}
+	err := c.FetchBatch(&maps)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(maps))
+	assert.Equal(t, true, maps[1]["extra"])
+	assert.Equal(t, map[string]interface{}{"m": float64(1)}, maps[0]["attrs"].(map[string]interface{})["n"])
+
+	c = testCursor(t, response)
+	var rows []batchRow
+	err = c.FetchBatch(&rows)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, []string{"x", "y"}, rows[0].Tags)
+	assert.Equal(t, "b", rows[1].Name)
+	assert.Equal(t, map[string]interface{}{}, rows[2].Attrs)
+	// batch is returned once
+	assert.NotNil(t, c.FetchBatch(&rows))
+
+	// mixed types
+	c = testCursor(t, `{"result":[1,"two",{"three":3},[4]],"hasMore":false}`)
+	var mixed []interface{}
+	err = c.FetchBatch(&mixed)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{float64(1), "two", map[string]interface{}{"three": float64(3)}, []interface{}{float64(4)}}, mixed)
+
+	// generic values are decoded on demand
+	c = testCursor(t, `{"result":[1,"two"],"hasMore":false}`)
+	assert.Nil(t, c.Result)
+	values, err := c.Values()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{float64(1), "two"}, values)
+
+	c = testCursor(t, `{"result":[{"name":"a"},2],"hasMore":false}`)
+	err = c.FetchBatch(&rows)
+	assert.NotNil(t, err)
+}
+
+func TestFetchBatchField(t *testing.T) {
+	c := testCursor(t, `{"result":[{"items":[1,2]},{"items":[3]},{"other":1},5],"hasMore":false}`)
+	var items [][]int
+	err := c.FetchBatchField("items", &items)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1, 2}, {3}}, items)
+}
+
+func TestPrefetch(t *testing.T) {
+	c := testCursor(t, `{"result":[{"name":"a"},{"name":"b"}],"hasMore":false}`)
+	p := c.Prefetch(context.Background())
+	var names []string
+	var row batchRow
+	for {
+		ok, err := p.FetchNext(&row)
+		assert.Nil(t, err)
+		if !ok {
+			break
+		}
+		names = append(names, row.Name)
+	}
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Nil(t, p.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p = testCursor(t, `{"result":[1],"hasMore":false}`).Prefetch(ctx)
+	cancel()
+	var n int
+	ok, err := p.FetchNext(&n)
+	if err == nil {
+		// batch was received before cancel
+		assert.True(t, ok)
+	}
+	assert.Nil(t, p.Close())
+}
+
+func TestNext(t *testing.T) {
+	c := testCursor(t, `{"result":[{"name":"a"},{"name":"b"},{"name":"c"}],"hasMore":false}`)
+	var names []string
+	var row batchRow
+	for c.Next(&row) {
+		names = append(names, row.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+	assert.Equal(t, 2, c.max)
+}
+
+func TestFetchAll(t *testing.T) {
+	c := testCursor(t, `{"result":[{"name":"a"},{"name":"b"},{"name":"c"}],"hasMore":false}`)
+	var row batchRow
+	c.FetchNext(&row)
+	rows := []batchRow{{Name: "x"}}
+	assert.Nil(t, c.FetchAll(&rows))
+	assert.Equal(t, []batchRow{{Name: "x"}, {Name: "b"}, {Name: "c"}}, rows)
+
+	assert.NotNil(t, c.FetchAll(rows))
+
+	c = testCursor(t, `{"result":[1,2,3],"hasMore":false}`)
+	var ns []int
+	assert.Equal(t, ErrTooManyResults, c.FetchAllMax(&ns, 2))
+	c = testCursor(t, `{"result":[1,2,3],"hasMore":false}`)
+	assert.Nil(t, c.FetchAllMax(&ns, 3))
+	assert.Equal(t, []int{1, 2, 3}, ns)
+}
+
+func TestAll(t *testing.T) {
+	c := testCursor(t, `{"result":[{"name":"a"},{"name":"b"},2],"hasMore":false}`)
+	var names []string
+	var errs int
+	for row, err := range All[batchRow](c) {
+		if err != nil {
+			errs++
+			continue
+		}
+		names = append(names, row.Name)
+	}
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Equal(t, 1, errs)
+
+	c = testCursor(t, `{"result":[1,2,3],"hasMore":false}`)
+	for n, err := range All[int](c) {
+		assert.Nil(t, err)
+		if n == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, c.Index)
+}
+
+func TestLargeInts(t *testing.T) {
+	c := testCursor(t, `{"result":[{"id":9007199254740993,"items":[9007199254740995]}],"hasMore":false}`)
+	var row struct {
+		Id int64 `json:"id"`
+	}
+	ok, err := c.FetchNext(&row)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), row.Id)
+
+	var items [][]int64
+	assert.Nil(t, c.FetchBatchField("items", &items))
+	assert.Equal(t, [][]int64{{9007199254740995}}, items)
+}
+
+func TestWarnings(t *testing.T) {
+	c := testCursor(t, `{"result":[],"hasMore":false,"extra":{"warnings":[{"code":1203,"message":"collection not found"}]}}`)
+	assert.True(t, c.HasWarnings())
+	assert.Equal(t, []Warning{{Code: 1203, Message: "collection not found"}}, c.Warnings())
+
+	c = testCursor(t, `{"result":[],"hasMore":false}`)
+	assert.False(t, c.HasWarnings())
+	assert.Equal(t, 0, len(c.Warnings()))
+}
+
+func TestIterate(t *testing.T) {
+	c := testCursor(t, `{"result":[{"name":"a"},{"name":"b"},{"name":"c"}],"hasMore":false}`)
+	var row batchRow
+	c.FetchNext(&row)
+	var names []string
+	err := c.Iterate(func(raw json.RawMessage) error {
+		var r batchRow
+		err := json.Unmarshal(raw, &r)
+		names = append(names, r.Name)
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b", "c"}, names)
+
+	c = testCursor(t, `{"result":[1,2,3],"hasMore":false}`)
+	stop := errors.New("stop")
+	assert.Equal(t, stop, c.Iterate(func(json.RawMessage) error { return stop }))
+	assert.Equal(t, 1, c.Index)
+}
+
+// server response with a batch of n documents
+func benchBatch(n int) []byte {
+	rows := make([]map[string]interface{}, n)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"name": "user" + strconv.Itoa(i), "tags": []string{"a", "b"}, "attrs": map[string]interface{}{"n": i}}
+	}
+	body, _ := json.Marshal(map[string]interface{}{"result": rows, "hasMore": false})
+	return body
+}
+
+// cursor decoded from the batch response, as done for every server response
+func benchCursor(b *testing.B, body []byte) *Cursor {
+	var c Cursor
+	if err := json.Unmarshal(body, &c); err != nil {
+		b.Fatal(err)
+	}
+	return &c
+}
+
+// decoding every result marshaling it again, as FetchNext did
+func BenchmarkDecodeRoundTrip(b *testing.B) {
+	body := benchBatch(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := benchCursor(b, body)
+		values, _ := c.Values()
+		for _, v := range values {
+			var row batchRow
+			if err := c.decode(v, &row); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkFetchNext(b *testing.B) {
+	body := benchBatch(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := benchCursor(b, body)
+		var row batchRow
+		for {
+			ok, err := c.FetchNext(&row)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkIterate(b *testing.B) {
+	body := benchBatch(1000)
+	b.ResetTimer()
+	var row batchRow
+	for i := 0; i < b.N; i++ {
+		c := benchCursor(b, body)
+		err := c.Iterate(func(raw json.RawMessage) error {
+			return json.Unmarshal(raw, &row)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestConcurrentFetchNext(t *testing.T) {
+	rows := make([]int, 500)
+	batches := make([]string, 5)
+	for i := range rows {
+		rows[i] = i
+	}
+	for i := range batches {
+		b, _ := json.Marshal(rows[i*100 : (i+1)*100])
+		batches[i] = string(b)
+	}
+	c, _ := batchCursor(t, batches...)
+	c.SetPrefetch(true)
+
+	seen := make(chan int, len(rows))
+	done := make(chan struct{})
+	for g := 0; g < 8; g++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			var n int
+			for c.Next(&n) {
+				seen <- n
+			}
+		}()
+	}
+	for g := 0; g < 8; g++ {
+		<-done
+	}
+	close(seen)
+	got := make(map[int]bool)
+	for n := range seen {
+		assert.False(t, got[n])
+		got[n] = true
+	}
+	assert.Equal(t, len(rows), len(got))
+}
+
+// transport blocking batch requests until they are cancelled, counting deletions.
+// Queries are answered with created.
+type prefetchTransport struct {
+	started chan struct{}
+	created string
+	mu      sync.Mutex
+	deleted int
+}
+
+func (p *prefetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "POST" {
+		return &http.Response{StatusCode: 201, Body: io.NopCloser(strings.NewReader(p.created)), Request: req}, nil
+	}
+	if req.Method == "DELETE" {
+		p.mu.Lock()
+		p.deleted++
+		p.mu.Unlock()
+		return &http.Response{StatusCode: 202, Body: io.NopCloser(strings.NewReader(`{"id":"1"}`)), Request: req}, nil
+	}
+	p.started <- struct{}{}
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func (p *prefetchTransport) deletes() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.deleted
+}
+
+// cursor with first batch of rows, sending next batches of rows to server transport
+func batchCursor(t *testing.T, batches ...string) (*Cursor, *serverTransport) {
+	st := &serverTransport{}
+	for i, b := range batches[1:] {
+		more := strconv.FormatBool(i < len(batches)-2)
+		st.responses = append(st.responses, testResponse{200, `{"id":"1","result":` + b + `,"hasMore":` + more + `}`})
+	}
+	c := testCursor(t, `{"id":"1","result":`+batches[0]+`,"hasMore":true}`)
+	c.db = testDB(st)
+	return c, st
+}
+
+func TestPrefetchBatches(t *testing.T) {
+	c, st := batchCursor(t, `[1,2]`, `[3,4]`, `[5]`)
+	c.SetPrefetch(true)
+	var got []int
+	var n int
+	for c.Next(&n) {
+		got = append(got, n)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, got)
+	assert.Equal(t, 2, len(st.reqs))
+	assert.Equal(t, "PUT", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/cursor/1", st.reqs[1].URL.Path)
+
+	// FetchOne takes the batch prefetched by FetchNext
+	c, st = batchCursor(t, `[1]`, `[2]`, `[3]`)
+	c.SetPrefetch(true)
+	got = nil
+	c.FetchNext(&n)
+	got = append(got, n)
+	for c.FetchOne(&n) {
+		if c.Index > 0 {
+			got = append(got, n)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3}, got)
+	assert.Equal(t, 2, len(st.reqs))
+
+	// cancelling the prefetch context aborts the batch in flight
+	pt := &prefetchTransport{started: make(chan struct{}, 1)}
+	c = testCursor(t, `{"id":"1","result":[1],"hasMore":true}`)
+	c.db = testDB(pt)
+	ctx, cancel := context.WithCancel(context.Background())
+	p := c.Prefetch(ctx)
+	ok, err := p.FetchNext(&n)
+	assert.True(t, ok)
+	<-pt.started
+	cancel()
+	_, err = p.FetchNext(&n)
+	assert.Equal(t, context.Canceled, err)
+
+	// Close cancels the batch in flight instead of waiting for it, and deletes the cursor
+	c = testCursor(t, `{"id":"1","result":[1],"hasMore":true}`)
+	c.db = testDB(pt)
+	p = c.Prefetch(context.Background())
+	p.FetchNext(&n)
+	<-pt.started
+	closed := make(chan error)
+	go func() { closed <- p.Close() }()
+	select {
+	case err = <-closed:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close waited for prefetch")
+	}
+	assert.Equal(t, 1, pt.deletes())
+}
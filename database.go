@@ -1,8 +1,10 @@
 package arango
 
 import (
+	"context"
 	"errors"
-	"regexp"
+	"net/http"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -18,6 +20,11 @@ type Database struct {
 	Collections []Collection
 	sess        *Session
 	baseURL     string
+	retries     RetryPolicy
+	// stream transaction id sent with requests
+	trx string
+	// context of every request
+	ctx context.Context
 }
 
 /*
@@ -28,8 +35,28 @@ type DatabaseResult struct {
 }
 */
 
+// WithContext returns database sending every request with ctx, cancelling them when ctx is done.
+// Collections and cursors got from it use ctx too.
+func (d *Database) WithContext(ctx context.Context) *Database {
+	db := *d
+	db.ctx = ctx
+	return &db
+}
+
+// context returns context of database requests
+func (d *Database) context() context.Context {
+	if d == nil || d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
 // Execute AQL query into server and returns cursor struct
 func (d *Database) Execute(q *Query) (*Cursor, error) {
+	return d.execute(d.context(), q)
+}
+
+func (d *Database) execute(ctx context.Context, q *Query) (*Cursor, error) {
 	if q == nil {
 		return nil, errors.New("Cannot execute nil query")
 	} else {
@@ -39,31 +66,116 @@ func (d *Database) Execute(q *Query) (*Cursor, error) {
 				return nil, errors.New(q.ErrorMsg)
 			}
 		}
+		if _, ok := q.Options["forceOneShardAttributeValue"]; ok {
+			role, err := d.sess.ServerRole()
+			if err != nil {
+				return nil, err
+			}
+			if role != "COORDINATOR" {
+				return nil, errors.New("forceOneShardAttributeValue is only valid in a cluster coordinator")
+			}
+		}
+		if pq := q.projected(nil); pq != q {
+			q = pq
+		} else if len(q.Projection) > 0 {
+			d.sess.warn("projection ignored, query has custom RETURN")
+		}
 		// create cursor
 		c := NewCursor(d)
+		c.query = q
 		t0 := time.Now()
-		_, err := d.send("cursor", "", "POST", q, c, c)
+		res, err := c.request(ctx, "", "POST", q)
 		t1 := time.Now()
 		if err != nil {
 			return nil, err
 		}
-		c.max = len(c.Result) - 1
 		c.Time = t1.Sub(t0)
 
-		if c.Err && err == nil {
-			err = errors.New(strconv.Itoa(c.ErrCode()) + ": " + c.ErrMsg)
-		} else if c.Err && err != nil {
-			err = errors.New("Execute err: " + err.Error() + ";; Cursor err: " + strconv.Itoa(c.ErrCode()) + ": " + c.ErrMsg)
-		}
-
-		if err != nil {
-			return nil, err
+		if c.Err {
+			return nil, &ArangoError{Code: c.Code, ErrorNum: c.ErrorNum, Message: c.ErrMsg, Header: responseHeader(res)}
 		}
 
 		return c, nil
 	}
 }
 
+// ExecuteOpts executes query like Execute, setting options
+func (d *Database) ExecuteOpts(q *Query, opts QueryOptions) (*Cursor, error) {
+	if q == nil {
+		return nil, errors.New("Cannot execute nil query")
+	}
+	oq := *q
+	oq.Options = make(map[string]interface{})
+	for k, v := range q.Options {
+		oq.Options[k] = v
+	}
+	oq.SetOptions(opts)
+	return d.Execute(&oq)
+}
+
+// time left to the client between server maxRuntime and ctx deadline
+const maxRuntimeBuffer = 100 * time.Millisecond
+
+// ExecuteContext executes query like Execute, request is cancelled when ctx is done. If ctx has a deadline,
+// query maxRuntime option is set to the time remaining, so the server kills the query when the client gives up.
+func (d *Database) ExecuteContext(ctx context.Context, q *Query) (*Cursor, error) {
+	if q == nil {
+		return nil, errors.New("Cannot execute nil query")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return d.execute(ctx, q)
+	}
+
+	left := time.Until(deadline)
+	if left <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	if left > maxRuntimeBuffer {
+		left -= maxRuntimeBuffer
+	}
+	dq := *q
+	dq.Options = make(map[string]interface{})
+	for k, v := range q.Options {
+		dq.Options[k] = v
+	}
+	dq.Options["maxRuntime"] = left.Seconds()
+
+	return d.execute(ctx, &dq)
+}
+
+// QueryAll executes aql with bindVars and decodes all results into r, a pointer to slice.
+func (d *Database) QueryAll(aql string, bindVars map[string]interface{}, r interface{}) error {
+	q := NewQuery(aql)
+	for k, v := range bindVars {
+		q.BindVars[k] = v
+	}
+	c, err := d.Execute(q)
+	if err != nil {
+		return err
+	}
+	return c.FetchAll(r)
+}
+
+// executeList executes query returning a single list, decoding it into result
+func (d *Database) executeList(q *Query, result interface{}) error {
+	kind := reflect.ValueOf(result).Elem().Kind()
+	if kind != reflect.Slice {
+		return errors.New("Container must be Slice kind")
+	}
+	c, err := d.Execute(q)
+	if err != nil {
+		return err
+	}
+	if c.batchLen() != 1 {
+		return errors.New("Invalid query result")
+	}
+	return c.decodeRow(0, result)
+}
+
 // ExecuteTran executes transaction into the database
 func (d *Database) ExecuteTran(t *Transaction) error {
 	if t.Action == "" {
@@ -109,40 +221,129 @@ func (d *Database) IsValid(q *Query) bool {
 
 // Do a request to test if the database is up and user authorized to use it
 func (d *Database) get(resource string, id string, method string, param *nap.Params, result, err interface{}) (*nap.Response, error) {
+	return d.getCtx(d.context(), resource, id, method, param, result, err)
+}
+
+// getCtx is like get, cancelling request when ctx is done
+func (d *Database) getCtx(ctx context.Context, resource string, id string, method string, param *nap.Params, result, err interface{}) (*nap.Response, error) {
 	url := d.buildRequest(resource, id)
-	var r *nap.Response
-	var e error
-
-	switch method {
-	case "OPTIONS":
-		r, e = d.sess.nap.Options(url, result, err)
-	case "HEAD":
-		r, e = d.sess.nap.Head(url, result, err)
-	case "DELETE":
-		r, e = d.sess.nap.Delete(url, result, err)
-	default:
-		r, e = d.sess.nap.Get(url, param, result, err)
-	}
+	s := d.napSession(ctx)
+
+	r, e := d.withRetry(ctx, resource, id, method, func() (*nap.Response, error) {
+		switch method {
+		case "OPTIONS":
+			return s.Options(url, result, err)
+		case "HEAD":
+			return s.Head(url, result, err)
+		case "DELETE":
+			return s.Delete(url, result, err)
+		default:
+			return s.Get(url, param, result, err)
+		}
+	})
 
-	return r, e
+	return r, ctxErr(ctx, e)
 }
 
 func (d *Database) send(resource string, id string, method string, payload, result, err interface{}) (*nap.Response, error) {
+	return d.sendCtx(d.context(), resource, id, method, payload, result, err)
+}
+
+// sendCtx is like send, cancelling request when ctx is done
+func (d *Database) sendCtx(ctx context.Context, resource string, id string, method string, payload, result, err interface{}) (*nap.Response, error) {
 	url := d.buildRequest(resource, id)
-	var r *nap.Response
-	var e error
-
-	switch method {
-	case "POST":
-		r, e = d.sess.nap.Post(url, payload, result, err)
-	case "PUT":
-		r, e = d.sess.nap.Put(url, payload, result, err)
-	case "PATCH":
-		r, e = d.sess.nap.Patch(url, payload, result, err)
-	case "DELETE":
-		r, e = d.sess.nap.Delete(url, result, err)
-	}
-	return r, e
+	s := d.napSession(ctx)
+
+	r, e := d.withRetry(ctx, resource, id, method, func() (*nap.Response, error) {
+		switch method {
+		case "POST":
+			return s.Post(url, payload, result, err)
+		case "PUT":
+			return s.Put(url, payload, result, err)
+		case "PATCH":
+			return s.Patch(url, payload, result, err)
+		case "DELETE":
+			return s.Delete(url, result, err)
+		}
+		return nil, nil
+	})
+	return r, ctxErr(ctx, e)
+}
+
+// request sends request to resource adding custom headers
+func (d *Database) request(resource string, id string, method string, header http.Header, payload, result, err interface{}) (*nap.Response, error) {
+	res, e := d.withRetryIf(d.context(), method, resource, idempotentHeader(resource, id, method, header), func() (*nap.Response, error) {
+		req := nap.Request{
+			Method:  method,
+			Url:     d.buildRequest(resource, id),
+			Payload: payload,
+			Result:  result,
+			Error:   err,
+			Header:  &header,
+		}
+		return d.napSession(d.context()).Send(&req)
+	})
+	return res, ctxErr(d.context(), e)
+}
+
+// napSession returns nap session sending requests with ctx, inside stream transaction if any
+func (d *Database) napSession(ctx context.Context) *nap.Session {
+	s := d.sess.napCtx(ctx)
+	if d.trx == "" {
+		return s
+	}
+	ns := *s
+	h := http.Header{}
+	if s.Header != nil {
+		for k, v := range *s.Header {
+			h[k] = v
+		}
+	}
+	h.Set("x-arango-trx-id", d.trx)
+	ns.Header = &h
+	return &ns
+}
+
+// Response of a raw request
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Request sends a request to the database API, for operations not covered by the driver. resource is relative
+// to the database API url, like "collection/users/figures". Body is decoded into result if it's not nil,
+// server errors are returned as *ArangoError, both carrying the response headers.
+func (d *Database) Request(method string, resource string, header http.Header, payload, result interface{}) (*Response, error) {
+	if header == nil {
+		header = http.Header{}
+	}
+	var e ArangoError
+	req := nap.Request{
+		Method:              method,
+		Url:                 d.buildRequest(resource, ""),
+		Payload:             payload,
+		Result:              result,
+		Error:               &e,
+		Header:              &header,
+		CaptureResponseBody: true,
+	}
+	ctx := d.context()
+	res, err := d.withRetryIf(ctx, method, resource, idempotentHeader(resource, "", method, header), func() (*nap.Response, error) {
+		r := req
+		return d.napSession(ctx).Send(&r)
+	})
+	if err != nil {
+		return nil, ctxErr(ctx, err)
+	}
+
+	r := &Response{Status: res.Status(), Header: responseHeader(res), Body: []byte(res.RawText())}
+	if r.Status >= 400 {
+		e.Code = r.Status
+		e.Header = r.Header
+		return r, &e
+	}
+	return r, nil
 }
 
 func (db Database) buildRequest(t string, id string) string {
@@ -183,12 +384,7 @@ func (db Database) Col(name string) *Collection {
 }
 
 func validColName(name string) error {
-	reg, err := regexp.Compile(`^[A-z]+[0-9\-_]*`)
-
-	if err != nil {
-		return err
-	}
-	if !reg.MatchString(name) {
+	if !nameReg.MatchString(name) {
 		return errors.New("Invalid collection name")
 	}
 
@@ -214,8 +410,63 @@ func (d *Database) CreateCollection(c *CollectionOptions) error {
 		Collections(d)
 		return nil
 	default:
-		return errors.New("Failed to create collection")
+		return statusError(resp)
+	}
+}
+
+// CreateEdgeCollection creates an edge collection and returns it.
+// Edge collections always have an edge index on _from and _to, no extra index is needed.
+func (d *Database) CreateEdgeCollection(name string, opts CollectionOptions) (*Collection, error) {
+	opts.Name = name
+	opts.IsEdge()
+	err := d.CreateCollection(&opts)
+	if err != nil {
+		return nil, err
+	}
+	col := d.Col(name)
+	if col.Type != 3 {
+		return nil, errors.New("Collection " + name + " is not an edge collection")
+	}
+	return col, nil
+}
+
+// EnsureCollection creates collection if it doesn't exist, or returns the existing one.
+// Fails if the existing collection type doesn't match options type.
+func (d *Database) EnsureCollection(opts CollectionOptions) (*Collection, error) {
+	err := validColName(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	// document collection by default
+	if opts.Type == 0 {
+		opts.Type = 2
+	}
+
+	resp, err := d.send("collection", "", "POST", &opts, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Status() {
+	case 200:
+	case 409:
+		// already exists, check type
+		current := d.CheckCollection(opts.Name)
+		if current == nil {
+			return nil, errors.New("Failed to read existing collection " + opts.Name)
+		}
+		if current.Type != opts.Type {
+			return nil, errors.New("Collection " + opts.Name + " exists with type " + strconv.Itoa(int(current.Type)) + ", expected " + strconv.Itoa(int(opts.Type)))
+		}
+	default:
+		return nil, statusError(resp)
+	}
+
+	err = Collections(d)
+	if err != nil {
+		return nil, err
 	}
+	return d.Col(opts.Name), nil
 }
 
 //Drop Collection
@@ -230,7 +481,7 @@ func (d *Database) DropCollection(name string) error {
 	case 200:
 		return nil
 	default:
-		return errors.New("Failed to create collection")
+		return statusError(resp)
 	}
 }
 
@@ -250,7 +501,7 @@ func (d *Database) TruncateCollection(name string) error {
 	case 202:
 		return nil
 	default:
-		return errors.New("Failed to truncate collection")
+		return statusError(resp)
 	}
 }
 
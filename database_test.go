@@ -0,0 +1,34 @@
+package arango
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseContext(t *testing.T) {
+	var d Database
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cd := d.WithContext(ctx)
+	assert.Equal(t, ctx, cd.context())
+	assert.Equal(t, context.Background(), d.context())
+	c := NewCursor(cd)
+	assert.Equal(t, ctx, c.db.context())
+	// a cancelled ctx aborts requests in flight
+	db := testDB(blockingTransport{})
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := db.WithContext(ctx).Request("GET", "version", nil, nil, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// stream transaction header is sent with raw requests
+	st := &serverTransport{responses: []testResponse{{200, `{}`}}}
+	db = testDB(st)
+	db.trx = "7"
+	_, err = db.Request("GET", "version", nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "7", st.reqs[0].Header.Get("x-arango-trx-id"))
+}
@@ -1,19 +1,93 @@
 package arango
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 )
 
+// ErrDocumentNotFound is returned when document doesn't exist in server
+var ErrDocumentNotFound error = &ArangoError{Code: 404, ErrorNum: 1202, Message: "Document not found"}
+
+// ErrRevMismatch is returned when document was modified since its revision
+var ErrRevMismatch error = &ArangoError{Code: 412, ErrorNum: 1200, Message: "Document revision mismatch"}
+
+// ErrRevisionConflict is returned by conditional operations when document revision isn't the expected one
+var ErrRevisionConflict = ErrRevMismatch
+
+// ReturnDocs decodes old and new document versions from the response of a write, nil fields are not requested.
+type ReturnDocs struct {
+	Old interface{}
+	New interface{}
+}
+
+// params adds returnOld and returnNew to resource
+func (r ReturnDocs) params(resource string) string {
+	params := url.Values{}
+	if r.Old != nil {
+		params.Set("returnOld", "true")
+	}
+	if r.New != nil {
+		params.Set("returnNew", "true")
+	}
+	if len(params) == 0 {
+		return resource
+	}
+	return resource + "?" + params.Encode()
+}
+
+// decode decodes old and new documents of result
+func (r ReturnDocs) decode(old, new json.RawMessage) error {
+	if r.Old != nil && len(old) > 0 {
+		if err := json.Unmarshal(old, r.Old); err != nil {
+			return err
+		}
+	}
+	if r.New != nil && len(new) > 0 {
+		return json.Unmarshal(new, r.New)
+	}
+	return nil
+}
+
+// result of a document write with returnOld and returnNew
+type writeResult struct {
+	Document
+	Old json.RawMessage `json:"old"`
+	New json.RawMessage `json:"new"`
+}
+
 type Document struct {
 	Id  string `json:"_id,omitempty"              `
 	Rev string `json:"_rev,omitempty"             `
 	Key string `json:"_key,omitempty"             `
 
+	// Deprecated: Error and Message are replaced by errors returned as *ArangoError
 	Error   bool   `json:"error,omitempty"`
 	Message string `json:"errorMessage,omitempty"`
 }
 
+// GetKey returns document key, structs embedding Document implement Modeler
+func (d *Document) GetKey() string {
+	return d.Key
+}
+
+// GetCollection returns collection of document id, empty for new documents
+func (d *Document) GetCollection() string {
+	col, _, _ := strings.Cut(d.Id, "/")
+	return col
+}
+
+// GetError returns error message of last server response
+func (d *Document) GetError() (string, bool) {
+	return d.Message, d.Error
+}
+
 // Creates base document structure
 func NewDocument(id string) (*Document, error) {
 	// some basic validation
@@ -26,10 +100,36 @@ func NewDocument(id string) (*Document, error) {
 	}
 	var d Document
 	d.Id = id
-	d.Key = sid[1]
+	if err := d.SetKey(sid[1]); err != nil {
+		return nil, err
+	}
 	return &d, nil
 }
 
+// ContentKey returns a key derived from the SHA-256 of v content, equal content returns the same key.
+// v is marshaled canonically, with objects keys sorted.
+func ContentKey(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	// decode and encode again to sort struct fields as map keys
+	var i interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err = dec.Decode(&i)
+	if err != nil {
+		return "", err
+	}
+	b, err = json.Marshal(i)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // Return map[string]string of document instead of struct
 func (d *Document) Map(db *Database) (map[string]string, error) {
 	var m map[string]string
@@ -42,8 +142,17 @@ func (d *Document) Map(db *Database) (map[string]string, error) {
 	return m, nil
 }
 
+// valid document key characters
+var validKey = regexp.MustCompile(`^[A-Za-z0-9_\-:.@()+,=;$!*'%]+$`)
+
+// SetKey sets document key, it must be 1 to 254 bytes of letters, digits or _ - : . @ ( ) + , = ; $ ! * ' %
 func (d *Document) SetKey(key string) error {
-	//valitated key
+	if key == "" || len(key) > 254 {
+		return errors.New("Invalid key length, must be between 1 and 254 bytes")
+	}
+	if !validKey.MatchString(key) {
+		return errors.New("Invalid key " + key + ", only letters, digits and _ - : . @ ( ) + , = ; $ ! * ' % are allowed")
+	}
 	d.Key = key
 	return nil
 }
@@ -53,7 +162,35 @@ func (d *Document) SetRev(rev string) error {
 	return nil
 }
 
-// Check if a document was updated
+// RefreshRev sets document revision to the current one in server, without fetching the document.
+func (d *Document) RefreshRev(db *Database) error {
+	if db == nil {
+		return errors.New("Invalid db")
+	}
+	if d.Id == "" {
+		return errors.New("Document must have valid _id")
+	}
+	res, err := db.get("document", d.Id, "HEAD", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		rev := strings.Trim(res.HttpResponse().Header.Get("Etag"), `"`)
+		if rev == "" {
+			return errors.New("Server did not return document revision")
+		}
+		d.Rev = rev
+		return nil
+	case 404:
+		return ErrDocumentNotFound
+	default:
+		return statusError(res)
+	}
+}
+
+// Check if a document was updated or deleted since its revision, using a HEAD request.
 func (d *Document) Updated(db *Database) (bool, error) {
 	if db == nil {
 		return false, errors.New("Invalid db")
@@ -62,20 +199,22 @@ func (d *Document) Updated(db *Database) (bool, error) {
 	if d.Id == "" || d.Rev == "" {
 		return false, errors.New("Document must exist or have valid _rev and _id")
 	}
-	// add revision id
-	res, err := db.get("document", d.Id+"?rev="+d.Rev, "GET", nil, nil, nil)
+	// server returns 304 if revision is the current one
+	header := http.Header{}
+	header.Set("If-None-Match", `"`+d.Rev+`"`)
+	res, err := db.request("document", d.Id, "HEAD", header, nil, nil, nil)
 
 	if err != nil {
 		return false, err
 	}
 
 	switch res.Status() {
-	case 404:
-		return true, nil
-	case 412:
+	case 304:
+		return false, nil
+	case 200, 404:
 		return true, nil
 	default:
-		return false, nil
+		return false, statusError(res)
 	}
 }
 
@@ -89,17 +228,117 @@ func (d *Document) Exist(db *Database) (bool, error) {
 	if d.Id == "" {
 		return false, errors.New("Document must exist or have valid _rev and _id")
 	}
-	// add revision id
-	res, err := db.get("document", d.Id, "GET", nil, nil, nil)
+	res, err := db.get("document", d.Id, "HEAD", nil, nil, nil)
 
 	if err != nil {
 		return false, err
 	}
 
 	switch res.Status() {
+	case 200:
+		return true, nil
 	case 404:
 		return false, nil
 	default:
+		return false, statusError(res)
+	}
+}
+
+// Replace replaces document with newDoc only if its revision is the current one, returns ErrRevMismatch if
+// the document was modified by someone else. Document revision is updated on success.
+func (d *Document) Replace(db *Database, newDoc interface{}) error {
+	return d.writeRev(db, "PUT", newDoc, ReturnDocs{})
+}
+
+// Update patches document like Replace, only if its revision is the current one.
+func (d *Document) Update(db *Database, patch interface{}) error {
+	return d.writeRev(db, "PATCH", patch, ReturnDocs{})
+}
+
+// ReplaceReturn is like Replace, decoding previous and stored versions of the document into ret.
+func (d *Document) ReplaceReturn(db *Database, newDoc interface{}, ret ReturnDocs) error {
+	return d.writeRev(db, "PUT", newDoc, ret)
+}
+
+// UpdateReturn is like Update, decoding previous and stored versions of the document into ret.
+func (d *Document) UpdateReturn(db *Database, patch interface{}, ret ReturnDocs) error {
+	return d.writeRev(db, "PATCH", patch, ret)
+}
+
+// Delete removes document only if its revision is the current one, returns ErrRevisionConflict otherwise.
+// Removed document is decoded into old if it's not nil.
+func (d *Document) Delete(db *Database, old interface{}) error {
+	return d.writeRev(db, "DELETE", nil, ReturnDocs{Old: old})
+}
+
+// GetIfChanged decodes document into doc only if it changed since its revision, returns false if it didn't.
+// Document revision is updated to the current one.
+//
+// Usage:
+//
+//	changed, err := user.GetIfChanged(db, &user)
+func (d *Document) GetIfChanged(db *Database, doc interface{}) (bool, error) {
+	if db == nil {
+		return false, errors.New("Invalid db")
+	}
+	if d.Id == "" || d.Rev == "" {
+		return false, errors.New("Document must exist or have valid _rev and _id")
+	}
+	header := http.Header{}
+	header.Set("If-None-Match", `"`+d.Rev+`"`)
+	var raw json.RawMessage
+	res, err := db.request("document", d.Id, "GET", header, nil, &raw, nil)
+	if err != nil {
+		return false, err
+	}
+
+	switch res.Status() {
+	case 304:
+		return false, nil
+	case 200:
+		var cur Document
+		if err := json.Unmarshal(raw, &cur); err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(raw, doc); err != nil {
+			return false, err
+		}
+		d.Rev = cur.Rev
 		return true, nil
+	case 404:
+		return false, ErrDocumentNotFound
+	default:
+		return false, statusError(res)
+	}
+}
+
+func (d *Document) writeRev(db *Database, method string, doc interface{}, ret ReturnDocs) error {
+	if db == nil {
+		return errors.New("Invalid db")
+	}
+	// check document id and rev
+	if d.Id == "" || d.Rev == "" {
+		return errors.New("Document must exist or have valid _rev and _id")
+	}
+	header := http.Header{}
+	header.Set("If-Match", `"`+d.Rev+`"`)
+	var result writeResult
+	res, err := db.request("document", ret.params(d.Id), method, header, doc, &result, &result)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200, 201, 202:
+		if method != "DELETE" {
+			d.Rev = result.Rev
+		}
+		return ret.decode(result.Old, result.New)
+	case 404:
+		return ErrDocumentNotFound
+	case 412:
+		return ErrRevisionConflict
+	default:
+		return statusError(res)
 	}
 }
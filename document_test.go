@@ -0,0 +1,61 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentKey(t *testing.T) {
+	type doc struct {
+		B int    `json:"b"`
+		A string `json:"a"`
+	}
+	k1, err := ContentKey(doc{B: 1, A: "x"})
+	assert.Nil(t, err)
+	k2, err := ContentKey(map[string]interface{}{"a": "x", "b": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, k1, k2)
+	assert.Equal(t, 64, len(k1))
+
+	k3, _ := ContentKey(doc{B: 2, A: "x"})
+	assert.NotEqual(t, k1, k3)
+}
+
+func TestSetKey(t *testing.T) {
+	var d Document
+	assert.Nil(t, d.SetKey("user_1:a@b.c(+),=;$!*'%-"))
+	assert.NotNil(t, d.SetKey(""))
+	assert.NotNil(t, d.SetKey("a/b"))
+	assert.NotNil(t, d.SetKey("a b"))
+	assert.NotNil(t, d.SetKey(strings.Repeat("a", 255)))
+
+	_, err := NewDocument("users/a b")
+	assert.NotNil(t, err)
+	doc, err := NewDocument("users/diego")
+	assert.Nil(t, err)
+	assert.Equal(t, "diego", doc.Key)
+}
+
+type taggedUser struct {
+	Document `arango:"collection=users"`
+	Name     string `json:"name" arango:"required"`
+	Role     string `json:"role" arango:"enum=admin|user"`
+}
+
+func TestReturnDocs(t *testing.T) {
+	assert.Equal(t, "users/a", ReturnDocs{}.params("users/a"))
+	var old, cur map[string]interface{}
+	r := ReturnDocs{Old: &old, New: &cur}
+	assert.Equal(t, "users/a?returnNew=true&returnOld=true", r.params("users/a"))
+
+	var res writeResult
+	assert.Nil(t, json.Unmarshal([]byte(`{"_id":"users/a","_rev":"2","old":{"n":1},"new":{"n":2}}`), &res))
+	assert.Nil(t, r.decode(res.Old, res.New))
+	assert.Equal(t, float64(1), old["n"])
+	assert.Equal(t, float64(2), cur["n"])
+	assert.True(t, errors.Is(ErrRevisionConflict, ErrConflict))
+}
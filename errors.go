@@ -0,0 +1,115 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	nap "github.com/diegogub/napping"
+)
+
+// Sentinel errors, use errors.Is to check errors returned by operations
+var (
+	// collection, document, graph or any other resource doesn't exist, status code 404
+	ErrNotFound = errors.New("Not found")
+	// unique constraint violation or revision mismatch, status code 409 or 412
+	ErrConflict = errors.New("Conflict")
+	// server can't be reached or is unavailable, status code 503
+	ErrArangoDown = errors.New("ArangoDB is down")
+	// ExecuteJS is not allowed, server must be started with --javascript.allow-admin-execute
+	ErrJSExecuteDisabled = errors.New("JavaScript execution is disabled")
+)
+
+// ArangoError is an error returned by the server
+type ArangoError struct {
+	// http status code
+	Code int `json:"code"`
+	// ArangoDB error number
+	ErrorNum int    `json:"errorNum"`
+	Message  string `json:"errorMessage"`
+	// response headers
+	Header http.Header `json:"-"`
+}
+
+func (e *ArangoError) Error() string {
+	return strconv.Itoa(e.Code) + ": " + e.Message
+}
+
+// Is matches sentinel errors by status code
+func (e *ArangoError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == 404
+	case ErrConflict:
+		return e.Code == 409 || e.Code == 412
+	case ErrArangoDown:
+		return e.Code == 503
+	}
+	return false
+}
+
+// statusError returns the server error of res decoded from its body, with status code and headers.
+// Returns nil if status code is not an error.
+func statusError(res *nap.Response) error {
+	if res.Status() < 400 {
+		return nil
+	}
+	e := &ArangoError{}
+	json.Unmarshal([]byte(res.RawText()), e)
+	e.Code = res.Status()
+	e.Header = responseHeader(res)
+	if e.Message == "" {
+		e.Message = http.StatusText(e.Code)
+	}
+	return e
+}
+
+// httpError returns an ArangoError with status code and message
+func httpError(code int, msg string) error {
+	return &ArangoError{Code: code, Message: msg}
+}
+
+// connError is a transport error, matches ErrArangoDown
+type connError struct {
+	err error
+}
+
+func (e *connError) Error() string {
+	return e.err.Error()
+}
+
+func (e *connError) Unwrap() []error {
+	return []error{e.err, ErrArangoDown}
+}
+
+// jsDisabledError is the server error of ExecuteJS when it's disabled, matches ErrJSExecuteDisabled
+type jsDisabledError struct {
+	err error
+}
+
+func (e *jsDisabledError) Error() string {
+	return ErrJSExecuteDisabled.Error() + ": " + e.err.Error()
+}
+
+func (e *jsDisabledError) Unwrap() []error {
+	return []error{e.err, ErrJSExecuteDisabled}
+}
+
+// downErr wraps errors connecting to server so they match ErrArangoDown
+func downErr(err error) error {
+	var op *net.OpError
+	if errors.Is(err, ErrCircuitOpen) || errors.As(err, &op) {
+		return &connError{err: err}
+	}
+	return err
+}
+
+// responseHeader returns headers of response, nil if there is none
+func responseHeader(res *nap.Response) http.Header {
+	if res == nil || res.HttpResponse() == nil {
+		return nil
+	}
+	return res.HttpResponse().Header
+}
@@ -0,0 +1,52 @@
+package arango
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	nap "github.com/diegogub/napping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorSentinels(t *testing.T) {
+	assert.True(t, errors.Is(ErrDocumentNotFound, ErrNotFound))
+	assert.True(t, errors.Is(ErrRevMismatch, ErrConflict))
+	assert.False(t, errors.Is(ErrRevMismatch, ErrNotFound))
+
+	var err error = &ArangoError{Code: 409, ErrorNum: 1210, Message: "unique constraint violated"}
+	assert.True(t, errors.Is(err, ErrConflict))
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 1210, ae.ErrorNum)
+
+	// errors of operations are decoded from server response
+	st := &serverTransport{responses: []testResponse{{409, `{"error":true,"code":409,"errorNum":1210,"errorMessage":"unique constraint violated"}`}}}
+	col := &Collection{db: testDB(st), Name: "users", Type: 2}
+	err = col.Save(map[string]string{"_key": "a"})
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 1210, ae.ErrorNum)
+	assert.Equal(t, "unique constraint violated", ae.Message)
+	assert.Equal(t, "/_db/shop/_api/document", st.reqs[0].URL.Path)
+
+	st.responses = []testResponse{{503, ``}}
+	err = col.Replace("a", map[string]string{})
+	assert.True(t, errors.Is(err, ErrArangoDown))
+	st.responses = []testResponse{{404, `{"error":true,"code":404,"errorNum":1203,"errorMessage":"collection not found"}`}}
+	assert.True(t, errors.Is(col.CreateHash(true, "name"), ErrNotFound))
+	assert.Nil(t, statusError(&nap.Response{}))
+
+	// unexpected statuses are returned as server errors too
+	st.responses = []testResponse{{503, ``}}
+	_, err = col.ListIndexes()
+	assert.True(t, errors.Is(err, ErrArangoDown))
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 503, ae.Code)
+	_, err = col.db.ListGraphs()
+	assert.True(t, errors.Is(err, ErrArangoDown))
+
+	err = downErr(&net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	assert.True(t, errors.Is(err, ErrArangoDown))
+	assert.False(t, errors.Is(downErr(errors.New("invalid character")), ErrArangoDown))
+}
@@ -0,0 +1,100 @@
+package arango
+
+import (
+	"errors"
+)
+
+// Execution plan of a query
+type QueryPlan struct {
+	Nodes         []PlanNode       `json:"nodes"`
+	Rules         []string         `json:"rules"`
+	Collections   []PlanCollection `json:"collections"`
+	EstimatedCost float64          `json:"estimatedCost"`
+	EstimatedRows int64            `json:"estimatedNrItems"`
+}
+
+type PlanNode struct {
+	Type          string                   `json:"type"`
+	Id            int                      `json:"id"`
+	Dependencies  []int                    `json:"dependencies"`
+	EstimatedCost float64                  `json:"estimatedCost"`
+	EstimatedRows int64                    `json:"estimatedNrItems"`
+	Collection    string                   `json:"collection,omitempty"`
+	Indexes       []map[string]interface{} `json:"indexes,omitempty"`
+}
+
+type PlanCollection struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// IndexesUsed returns names of indexes used by plan nodes
+func (p *QueryPlan) IndexesUsed() []string {
+	var names []string
+	for _, n := range p.Nodes {
+		for _, idx := range n.Indexes {
+			if name, ok := idx["name"].(string); ok && name != "" {
+				names = append(names, name)
+			} else if id, ok := idx["id"].(string); ok {
+				names = append(names, id)
+			}
+		}
+	}
+	return names
+}
+
+// Explain options
+type ExplainOptions struct {
+	// Max number of plans the optimizer creates
+	MaxNumberOfPlans int
+	// Optimizer rules to enable, or disable with a - prefix like "-use-indexes"
+	OptimizerRules []string
+}
+
+type explainResult struct {
+	Plan     QueryPlan `json:"plan"`
+	Warnings []Warning `json:"warnings"`
+	Error    bool      `json:"error"`
+	Message  string    `json:"errorMessage"`
+}
+
+// Explain returns execution plan chosen by the optimizer for query, without executing it
+func (d *Database) Explain(aql string, binds map[string]interface{}, opts ExplainOptions) (*QueryPlan, error) {
+	if aql == "" {
+		return nil, errors.New("Cannot explain empty query")
+	}
+	var r explainResult
+	payload := map[string]interface{}{"query": aql}
+	if len(binds) > 0 {
+		payload["bindVars"] = binds
+	}
+	options := map[string]interface{}{}
+	if opts.MaxNumberOfPlans > 0 {
+		options["maxNumberOfPlans"] = opts.MaxNumberOfPlans
+	}
+	if len(opts.OptimizerRules) > 0 {
+		options["optimizer"] = map[string]interface{}{"rules": opts.OptimizerRules}
+	}
+	if len(options) > 0 {
+		payload["options"] = options
+	}
+	res, err := d.send("explain", "", "POST", payload, &r, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return &r.Plan, nil
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// Explain returns execution plan of cursor query, explaining again the query text and bind parameters.
+func (c *Cursor) Explain() (*QueryPlan, error) {
+	if c.db == nil || c.query == nil {
+		return nil, errors.New("Cursor was not created by a query")
+	}
+	return c.db.Explain(c.query.Aql, c.query.BindVars, ExplainOptions{})
+}
@@ -0,0 +1,193 @@
+package arango
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// EndpointPolicy selects the coordinator used by each request
+type EndpointPolicy interface {
+	// Pick returns index of endpoint to use among n endpoints
+	Pick(n int) int
+	// Failed is called when endpoint i is unreachable
+	Failed(i int)
+}
+
+type roundRobin struct {
+	next uint64
+}
+
+// RoundRobin sends each request to the next endpoint
+func RoundRobin() EndpointPolicy {
+	return &roundRobin{}
+}
+
+func (p *roundRobin) Pick(n int) int {
+	return int((atomic.AddUint64(&p.next, 1) - 1) % uint64(n))
+}
+
+func (p *roundRobin) Failed(i int) {}
+
+type randomEndpoint struct{}
+
+// RandomEndpoint sends each request to a random endpoint
+func RandomEndpoint() EndpointPolicy {
+	return randomEndpoint{}
+}
+
+func (randomEndpoint) Pick(n int) int {
+	return rand.Intn(n)
+}
+
+func (randomEndpoint) Failed(i int) {}
+
+type sticky struct {
+	mu      sync.Mutex
+	current int
+}
+
+// Sticky sends every request to the same endpoint until it fails
+func Sticky() EndpointPolicy {
+	return &sticky{}
+}
+
+func (p *sticky) Pick(n int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// kept below n, so Failed matches the picked index
+	p.current %= n
+	return p.current
+}
+
+func (p *sticky) Failed(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == i {
+		p.current++
+	}
+}
+
+// ConnectCluster connects to a cluster sending requests to endpoints chosen by policy,
+// requests are sent to other coordinators if one is unreachable. Uses round robin if policy is nil.
+func ConnectCluster(endpoints []string, user, password string, log bool, policy EndpointPolicy) (*Session, error) {
+	f, err := newFailoverTransport(endpoints, policy, nil)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ConnectTransport(strings.TrimSuffix(endpoints[0], "/"), user, password, log, f)
+	if err != nil {
+		return nil, err
+	}
+	s.failover = f
+	return s, nil
+}
+
+// SetEndpoints sends session requests to endpoints chosen by policy, failing over to other coordinators
+// when one is unreachable. The session host is replaced by the chosen endpoint.
+func (s *Session) SetEndpoints(endpoints []string, policy EndpointPolicy) error {
+	if s.failover != nil {
+		f, err := newFailoverTransport(endpoints, policy, s.failover.next)
+		if err != nil {
+			return err
+		}
+		s.failover.mu.Lock()
+		s.failover.endpoints = f.endpoints
+		s.failover.policy = f.policy
+		s.failover.mu.Unlock()
+		return nil
+	}
+	c := s.client()
+	f, err := newFailoverTransport(endpoints, policy, c.Transport)
+	if err != nil {
+		return err
+	}
+	s.failover = f
+	c.Transport = f
+	return nil
+}
+
+type failoverTransport struct {
+	next      http.RoundTripper
+	mu        sync.Mutex
+	endpoints []*url.URL
+	policy    EndpointPolicy
+}
+
+func newFailoverTransport(endpoints []string, policy EndpointPolicy, next http.RoundTripper) (*failoverTransport, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("At least one endpoint is needed")
+	}
+	if policy == nil {
+		policy = RoundRobin()
+	}
+	f := &failoverTransport{next: nextTransport(next), policy: policy}
+	for _, e := range endpoints {
+		u, err := url.Parse(e)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, errors.New("Invalid endpoint " + e)
+		}
+		f.endpoints = append(f.endpoints, u)
+	}
+	return f, nil
+}
+
+func (f *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	endpoints := f.endpoints
+	policy := f.policy
+	f.mu.Unlock()
+
+	n := len(endpoints)
+	first := policy.Pick(n)
+	safe := req.Method == "GET" || req.Method == "HEAD" || req.Method == "OPTIONS"
+
+	var res *http.Response
+	var err error
+	for a := 0; a < n; a++ {
+		i := (first + a) % n
+		r := req.Clone(req.Context())
+		r.URL.Scheme = endpoints[i].Scheme
+		r.URL.Host = endpoints[i].Host
+		r.Host = ""
+		if a > 0 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				break
+			}
+			if r.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err = f.next.RoundTrip(r)
+		if err == nil && (res.StatusCode != 503 || !safe) {
+			return res, nil
+		}
+		// request may have been processed by coordinator
+		if err != nil && !safe && !notSent(err) {
+			return nil, err
+		}
+		if req.Context().Err() != nil {
+			return res, err
+		}
+		policy.Failed(i)
+		if res != nil && a < n-1 {
+			res.Body.Close()
+		}
+	}
+	return res, err
+}
+
+// notSent checks if request failed before being sent to server
+func notSent(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	var op *net.OpError
+	return errors.As(err, &op) && op.Op == "dial"
+}
@@ -0,0 +1,60 @@
+package arango
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailover(t *testing.T) {
+	ht := &hostsTransport{down: map[string]bool{"b:8529": true}}
+	f, err := newFailoverTransport([]string{"http://a:8529", "http://b:8529", "http://c:8529"}, RoundRobin(), ht)
+	assert.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("POST", "http://a:8529/_api/document/users", strings.NewReader(`{}`))
+		res, err := f.RoundTrip(req)
+		assert.Nil(t, err)
+		assert.Equal(t, 200, res.StatusCode)
+	}
+	assert.Equal(t, []string{"a:8529", "b:8529", "c:8529", "c:8529"}, ht.hosts)
+
+	ht.hosts = nil
+	f, _ = newFailoverTransport([]string{"http://b:8529", "http://a:8529"}, Sticky(), ht)
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "http://b:8529/_api/version", nil)
+		f.RoundTrip(req)
+	}
+	assert.Equal(t, []string{"b:8529", "a:8529", "a:8529"}, ht.hosts)
+
+	// sticky keeps moving to the next endpoint after a full cycle
+	p := Sticky()
+	var picks []int
+	for i := 0; i < 5; i++ {
+		n := p.Pick(2)
+		picks = append(picks, n)
+		p.Failed(n)
+	}
+	assert.Equal(t, []int{0, 1, 0, 1, 0}, picks)
+
+	_, err = newFailoverTransport([]string{"localhost"}, nil, nil)
+	assert.NotNil(t, err)
+}
+
+// roundTripper failing to connect to down hosts
+type hostsTransport struct {
+	down  map[string]bool
+	hosts []string
+}
+
+func (h *hostsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.hosts = append(h.hosts, req.URL.Host)
+	if h.down[req.URL.Host] {
+		return nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	}
+	return &http.Response{StatusCode: 200, Body: http.NoBody, Request: req}, nil
+}
@@ -0,0 +1,221 @@
+package arango
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	nap "github.com/diegogub/napping"
+)
+
+// Installed Foxx service
+type FoxxService struct {
+	Mount       string            `json:"mount"`
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Provides    map[string]string `json:"provides"`
+	Development bool              `json:"development"`
+	Legacy      bool              `json:"legacy"`
+}
+
+// Foxx service as returned after install, upgrade or replace
+type FoxxServiceInfo struct {
+	FoxxService
+	Path     string                 `json:"path"`
+	Manifest map[string]interface{} `json:"manifest"`
+	Options  map[string]interface{} `json:"options"`
+	Checksum string                 `json:"checksum"`
+}
+
+// Options to install, upgrade, replace or uninstall a Foxx service, nil values use server defaults
+type FoxxOptions struct {
+	// run setup script
+	Setup *bool
+	// run teardown script of the old service
+	Teardown *bool
+	// Legacy compatibility mode, for services written for ArangoDB 2.8
+	Legacy bool
+	// Upgrade or replace even if there is no service at mount, installing it
+	Force bool
+}
+
+func (o FoxxOptions) params(mount string) url.Values {
+	params := url.Values{}
+	params.Set("mount", mount)
+	if o.Setup != nil {
+		params.Set("setup", strconv.FormatBool(*o.Setup))
+	}
+	if o.Teardown != nil {
+		params.Set("teardown", strconv.FormatBool(*o.Teardown))
+	}
+	if o.Legacy {
+		params.Set("legacy", "true")
+	}
+	if o.Force {
+		params.Set("force", "true")
+	}
+	return params
+}
+
+// ListServices lists Foxx services of database, without system services
+func (d *Database) ListServices() ([]FoxxService, error) {
+	var services []FoxxService
+	var e ArangoError
+	res, err := d.get("foxx?excludeSystem=true", "", "GET", nil, &services, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return services, nil
+	default:
+		e.Code = res.Status()
+		return nil, &e
+	}
+}
+
+// InstallService installs service at mount from a zip bundle
+func (d *Database) InstallService(mount string, zip io.Reader, opts FoxxOptions) (*FoxxServiceInfo, error) {
+	return d.foxxBundle("POST", "foxx", mount, zip, opts, 201)
+}
+
+// UpgradeService upgrades service at mount from a zip bundle, keeping its configuration and dependencies
+func (d *Database) UpgradeService(mount string, zip io.Reader, opts FoxxOptions) (*FoxxServiceInfo, error) {
+	return d.foxxBundle("PATCH", "foxx/service", mount, zip, opts, 200)
+}
+
+// ReplaceService replaces service at mount from a zip bundle, discarding its configuration and dependencies
+func (d *Database) ReplaceService(mount string, zip io.Reader, opts FoxxOptions) (*FoxxServiceInfo, error) {
+	return d.foxxBundle("PUT", "foxx/service", mount, zip, opts, 200)
+}
+
+// UninstallService removes service at mount, running its teardown script unless opts.Teardown is false
+func (d *Database) UninstallService(mount string, opts FoxxOptions) error {
+	if mount == "" {
+		return errors.New("Invalid empty mount")
+	}
+	var e ArangoError
+	res, err := d.get("foxx/service?"+opts.params(mount).Encode(), "", "DELETE", nil, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 204:
+		return nil
+	default:
+		e.Code = res.Status()
+		return &e
+	}
+}
+
+// foxxBundle uploads zip bundle of a service
+func (d *Database) foxxBundle(method string, resource string, mount string, zip io.Reader, opts FoxxOptions, status int) (*FoxxServiceInfo, error) {
+	if mount == "" {
+		return nil, errors.New("Invalid empty mount")
+	}
+	if zip == nil {
+		return nil, errors.New("Invalid nil service bundle")
+	}
+	bundle, err := io.ReadAll(zip)
+	if err != nil {
+		return nil, err
+	}
+
+	var info FoxxServiceInfo
+	var e ArangoError
+	header := http.Header{}
+	header.Set("Content-Type", "application/zip")
+	res, err := d.withRetryIf(d.context(), method, resource, false, func() (*nap.Response, error) {
+		req := nap.Request{
+			Method:     method,
+			Url:        d.buildRequest(resource+"?"+opts.params(mount).Encode(), ""),
+			Payload:    bytes.NewBuffer(bundle),
+			RawPayload: true,
+			Result:     &info,
+			Error:      &e,
+			Header:     &header,
+		}
+		return d.napSession(d.context()).Send(&req)
+	})
+	if err != nil {
+		return nil, ctxErr(d.context(), err)
+	}
+
+	if res.Status() == status {
+		return &info, nil
+	}
+	e.Code = res.Status()
+	return nil, &e
+}
+
+// ServiceConfig returns configuration of service, option names to their current values
+func (d *Database) ServiceConfig(mount string) (map[string]interface{}, error) {
+	return d.foxxSettings("GET", "configuration", mount, nil)
+}
+
+// UpdateServiceConfig sets configuration options of service, keeping the others
+func (d *Database) UpdateServiceConfig(mount string, config map[string]interface{}) (map[string]interface{}, error) {
+	return d.foxxSettings("PATCH", "configuration", mount, config)
+}
+
+// ReplaceServiceConfig replaces whole configuration of service
+func (d *Database) ReplaceServiceConfig(mount string, config map[string]interface{}) (map[string]interface{}, error) {
+	return d.foxxSettings("PUT", "configuration", mount, config)
+}
+
+// ServiceDependencies returns dependencies of service, names to mount points
+func (d *Database) ServiceDependencies(mount string) (map[string]interface{}, error) {
+	return d.foxxSettings("GET", "dependencies", mount, nil)
+}
+
+// UpdateServiceDependencies sets dependencies of service, keeping the others
+func (d *Database) UpdateServiceDependencies(mount string, deps map[string]interface{}) (map[string]interface{}, error) {
+	return d.foxxSettings("PATCH", "dependencies", mount, deps)
+}
+
+// ReplaceServiceDependencies replaces all dependencies of service
+func (d *Database) ReplaceServiceDependencies(mount string, deps map[string]interface{}) (map[string]interface{}, error) {
+	return d.foxxSettings("PUT", "dependencies", mount, deps)
+}
+
+// foxxSettings reads or writes configuration or dependencies of service
+func (d *Database) foxxSettings(method string, kind string, mount string, values map[string]interface{}) (map[string]interface{}, error) {
+	if mount == "" {
+		return nil, errors.New("Invalid empty mount")
+	}
+	params := url.Values{}
+	params.Set("mount", mount)
+	// values instead of option definitions
+	params.Set("minimal", "true")
+	resource := "foxx/" + kind + "?" + params.Encode()
+
+	var result map[string]interface{}
+	var e ArangoError
+	var res *nap.Response
+	var err error
+	if method == "GET" {
+		res, err = d.get(resource, "", method, nil, &result, &e)
+	} else {
+		res, err = d.send(resource, "", method, values, &result, &e)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		// writes return values and warnings
+		if m, ok := result["values"].(map[string]interface{}); ok && method != "GET" {
+			return m, nil
+		}
+		return result, nil
+	default:
+		e.Code = res.Status()
+		return nil, &e
+	}
+}
@@ -0,0 +1,104 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallService(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{201, `{"mount":"/app","name":"app","version":"1.0.0","checksum":"abc","manifest":{"main":"index.js"}}`},
+	}}
+	db := testDB(st)
+	setup := false
+	info, err := db.InstallService("/app", strings.NewReader("PK zip"), FoxxOptions{Setup: &setup, Legacy: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "/app", info.Mount)
+	assert.Equal(t, "abc", info.Checksum)
+	assert.Equal(t, "index.js", info.Manifest["main"])
+
+	req := st.reqs[0]
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/_db/shop/_api/foxx", req.URL.Path)
+	assert.Equal(t, "application/zip", req.Header.Get("Content-Type"))
+	q := req.URL.Query()
+	assert.Equal(t, "/app", q.Get("mount"))
+	assert.Equal(t, "false", q.Get("setup"))
+	assert.Equal(t, "true", q.Get("legacy"))
+	assert.Equal(t, "", q.Get("force"))
+	body, _ := io.ReadAll(req.Body)
+	assert.Equal(t, "PK zip", string(body))
+
+	st.responses = []testResponse{{200, `{"mount":"/app","version":"1.1.0"}`}}
+	st.reqs = nil
+	info, err = db.UpgradeService("/app", strings.NewReader("PK"), FoxxOptions{Force: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.1.0", info.Version)
+	assert.Equal(t, "PATCH", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/foxx/service", st.reqs[0].URL.Path)
+	assert.Equal(t, "true", st.reqs[0].URL.Query().Get("force"))
+
+	st.responses = []testResponse{{400, `{"error":true,"code":400,"errorNum":3011,"errorMessage":"service already exists"}`}}
+	_, err = db.ReplaceService("/app", strings.NewReader("PK"), FoxxOptions{})
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 3011, ae.ErrorNum)
+	assert.Equal(t, "PUT", st.reqs[1].Method)
+
+	_, err = db.InstallService("", strings.NewReader("PK"), FoxxOptions{})
+	assert.NotNil(t, err)
+	_, err = db.InstallService("/app", nil, FoxxOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(st.reqs))
+}
+
+func TestServiceSettings(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{200, `[{"mount":"/app","name":"app","version":"1.0.0","provides":{}}]`},
+	}}
+	db := testDB(st)
+	services, err := db.ListServices()
+	assert.Nil(t, err)
+	assert.Equal(t, "app", services[0].Name)
+	assert.Equal(t, "true", st.reqs[0].URL.Query().Get("excludeSystem"))
+
+	st.responses = []testResponse{{200, `{"values":{"level":"debug"},"warnings":{}}`}}
+	st.reqs = nil
+	config, err := db.UpdateServiceConfig("/app", map[string]interface{}{"level": "debug"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"level": "debug"}, config)
+	req := st.reqs[0]
+	assert.Equal(t, "PATCH", req.Method)
+	assert.Equal(t, "/_db/shop/_api/foxx/configuration", req.URL.Path)
+	assert.Equal(t, "/app", req.URL.Query().Get("mount"))
+	assert.Equal(t, "true", req.URL.Query().Get("minimal"))
+	var body map[string]interface{}
+	assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{"level": "debug"}, body)
+
+	st.responses = []testResponse{{200, `{"auth":"/auth"}`}}
+	deps, err := db.ServiceDependencies("/app")
+	assert.Nil(t, err)
+	assert.Equal(t, "/auth", deps["auth"])
+	assert.Equal(t, "GET", st.reqs[1].Method)
+	assert.Equal(t, "/_db/shop/_api/foxx/dependencies", st.reqs[1].URL.Path)
+
+	st.responses = []testResponse{{204, ``}}
+	st.reqs = nil
+	teardown := false
+	assert.Nil(t, db.UninstallService("/app", FoxxOptions{Teardown: &teardown}))
+	assert.Equal(t, "DELETE", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/foxx/service", st.reqs[0].URL.Path)
+	assert.Equal(t, "false", st.reqs[0].URL.Query().Get("teardown"))
+
+	st.responses = []testResponse{{400, `{"error":true,"code":400,"errorNum":3009,"errorMessage":"service not found"}`}}
+	err = db.UninstallService("/none", FoxxOptions{})
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 3009, ae.ErrorNum)
+}
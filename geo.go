@@ -0,0 +1,113 @@
+package arango
+
+import (
+	"errors"
+	"strings"
+)
+
+// Nearest returns a cursor with the limit documents closest to lat, lon, using the collection geo index.
+// Unlike Near it runs an AQL query, simple queries were removed from server.
+func (c *Collection) Nearest(lat, lon float64, limit int) (*Cursor, error) {
+	if limit <= 0 {
+		return nil, errors.New("Invalid limit")
+	}
+	dist, err := c.geoDistance()
+	if err != nil {
+		return nil, err
+	}
+	q := NewQuery("FOR doc IN @@col SORT " + dist + " LIMIT @limit RETURN doc")
+	q.BindVars["@col"] = c.Name
+	q.BindVars["lat"] = lat
+	q.BindVars["lon"] = lon
+	q.BindVars["limit"] = limit
+	return c.db.Execute(q)
+}
+
+// WithinRadius returns a cursor with documents at most radius meters away from lat, lon, closest first.
+func (c *Collection) WithinRadius(lat, lon, radius float64) (*Cursor, error) {
+	if radius < 0 {
+		return nil, errors.New("Invalid radius")
+	}
+	dist, err := c.geoDistance()
+	if err != nil {
+		return nil, err
+	}
+	q := NewQuery("FOR doc IN @@col LET distance = " + dist + " FILTER distance <= @radius SORT distance RETURN doc")
+	q.BindVars["@col"] = c.Name
+	q.BindVars["lat"] = lat
+	q.BindVars["lon"] = lon
+	q.BindVars["radius"] = radius
+	return c.db.Execute(q)
+}
+
+// FulltextSearch returns a cursor with documents matching fulltext query in field, like "prefix:go,-java".
+// field must have a fulltext index.
+func (c *Collection) FulltextSearch(field, query string) (*Cursor, error) {
+	if field == "" || query == "" {
+		return nil, errors.New("Field and query must not be empty")
+	}
+	indexes, err := c.ListIndexes()
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, i := range indexes {
+		if i.Type == "fulltext" && len(i.Fields) == 1 && i.Fields[0] == field {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.New("No fulltext index on " + c.Name + "." + field)
+	}
+
+	q := NewQuery("FOR doc IN FULLTEXT(@@col, @field, @query) RETURN doc")
+	q.BindVars["@col"] = c.Name
+	q.BindVars["field"] = field
+	q.BindVars["query"] = query
+	return c.db.Execute(q)
+}
+
+// geoDistance returns AQL distance in meters from @lat, @lon to doc, using fields of collection geo index
+func (c *Collection) geoDistance() (string, error) {
+	indexes, err := c.ListIndexes()
+	if err != nil {
+		return "", err
+	}
+	for _, i := range indexes {
+		if d := geoIndexDistance(i); d != "" {
+			return d, nil
+		}
+	}
+	return "", errors.New("No geo index in collection " + c.Name)
+}
+
+// geoIndexDistance returns AQL distance to doc using index fields, empty if it's not a geo index
+func geoIndexDistance(i Index) string {
+	switch i.Type {
+	case "geo", "geo1", "geo2":
+	default:
+		return ""
+	}
+	switch {
+	case len(i.Fields) == 2:
+		return "DISTANCE(" + docField(i.Fields[0]) + ", " + docField(i.Fields[1]) + ", @lat, @lon)"
+	case len(i.Fields) == 1 && i.GeoJson:
+		return "GEO_DISTANCE([@lon, @lat], " + docField(i.Fields[0]) + ")"
+	case len(i.Fields) == 1:
+		// [lat, lon] array
+		f := docField(i.Fields[0])
+		return "DISTANCE(" + f + "[0], " + f + "[1], @lat, @lon)"
+	default:
+		return ""
+	}
+}
+
+// docField returns attribute path of doc, escaping its parts
+func docField(path string) string {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		parts[i] = EscapeName(p)
+	}
+	return "doc." + strings.Join(parts, ".")
+}
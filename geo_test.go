@@ -0,0 +1,14 @@
+package arango
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoIndexDistance(t *testing.T) {
+	assert.Equal(t, "DISTANCE(doc.lat, doc.lon, @lat, @lon)", geoIndexDistance(Index{Type: "geo", Fields: []string{"lat", "lon"}}))
+	assert.Equal(t, "GEO_DISTANCE([@lon, @lat], doc.`geo-loc`.point)", geoIndexDistance(Index{Type: "geo", GeoJson: true, Fields: []string{"geo-loc.point"}}))
+	assert.Equal(t, "DISTANCE(doc.loc[0], doc.loc[1], @lat, @lon)", geoIndexDistance(Index{Type: "geo", Fields: []string{"loc"}}))
+	assert.Equal(t, "", geoIndexDistance(Index{Type: "persistent", Fields: []string{"lat", "lon"}}))
+}
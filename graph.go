@@ -1,7 +1,10 @@
 package arango
 
 import (
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 )
 
 // Graph structure
@@ -30,16 +33,7 @@ func (g *Graph) Traverse(t *Traversal, r interface{}) error {
 		return err
 	}
 
-	switch res.Status() {
-	case 404:
-		return errors.New("Invalid collections or graph")
-	case 400:
-		return errors.New("Traversal specification is either missing or malformed")
-	case 500:
-		return errors.New("Error inside traversal or traversal performed more than maxIterations iterations.")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 // Remove Vertex
@@ -57,10 +51,8 @@ func (g *Graph) RemoveE(col string, key string) error {
 	case 200, 202:
 		return nil
 		// need to add conditional update
-	case 404, 412:
-		return errors.New("Invalid collection ,graph or document id")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -81,10 +73,8 @@ func (g *Graph) ReplaceE(col string, key string, doc interface{}, patch interfac
 	case 200, 202:
 		return nil
 		// need to add conditional update
-	case 404, 412:
-		return errors.New("Invalid collection ,graph or edge id")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -105,10 +95,8 @@ func (g *Graph) PatchE(col string, key string, doc interface{}, patch interface{
 	case 200, 202:
 		return nil
 		// need to add conditional update
-	case 404, 412:
-		return errors.New("Invalid collection ,graph or document id")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -128,10 +116,8 @@ func (g *Graph) GetE(col string, key string, edge interface{}) error {
 	switch res.Status() {
 	case 201, 202:
 		return nil
-	case 404:
-		return errors.New("Invalid collection or graph to save edge")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -151,11 +137,90 @@ func (g *Graph) E(col string, edge interface{}) error {
 	switch res.Status() {
 	case 201, 202:
 		return nil
+	default:
+		return statusError(res)
+	}
+}
+
+// Result of a single edge creation in AddEdges
+type EdgeResult struct {
+	Id      string `json:"_id"`
+	Key     string `json:"_key"`
+	Rev     string `json:"_rev"`
+	Error   bool   `json:"error"`
+	Num     int    `json:"errorNum"`
+	Message string `json:"errorMessage"`
+}
+
+// AddEdges creates edges into graph edge collection with one request, returning a result for every edge in order.
+// Edges failing (vertex collection not allowed by edge definition, duplicated key...) don't abort the rest of them.
+// Existence of vertices is not checked.
+func (g *Graph) AddEdges(col string, edges []Edge) ([]EdgeResult, error) {
+	if col == "" {
+		return nil, errors.New("Invalid collection name")
+	}
+	var def *EdgeDefinition
+	for i, ed := range g.EdgesDef {
+		if ed.Collection == col {
+			def = &g.EdgesDef[i]
+			break
+		}
+	}
+	if def == nil {
+		return nil, errors.New("Edge collection " + col + " not in graph " + g.Name)
+	}
+
+	results := make([]EdgeResult, len(edges))
+	// only valid edges are sent
+	var send []Edge
+	var pos []int
+	for i, e := range edges {
+		if !inVertexCols(e.From, def.From) || !inVertexCols(e.To, def.To) {
+			results[i].Error = true
+			results[i].Message = "Vertex collection not allowed in edge definition"
+			continue
+		}
+		send = append(send, e)
+		pos = append(pos, i)
+	}
+	if len(send) == 0 {
+		return results, nil
+	}
+
+	var sent []EdgeResult
+	res, err := g.db.send("document", col, "POST", send, &sent, &sent)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 201, 202:
+		if len(sent) != len(send) {
+			return nil, errors.New("Invalid number of results")
+		}
+		for i, r := range sent {
+			results[pos[i]] = r
+		}
+		return results, nil
 	case 404:
-		return errors.New("Invalid collection or graph to save edge")
+		return nil, statusError(res)
 	default:
-		return nil
+		return nil, statusError(res)
+	}
+}
+
+// inVertexCols checks if handle belongs to one of cols
+func inVertexCols(handle string, cols []string) bool {
+	sid := strings.Split(handle, "/")
+	if len(sid) != 2 || sid[1] == "" {
+		return false
+	}
+	for _, c := range cols {
+		if c == sid[0] {
+			return true
+		}
 	}
+	return false
 }
 
 // Remove Vertex
@@ -173,10 +238,8 @@ func (g *Graph) RemoveV(col string, key string) error {
 	case 200, 202:
 		return nil
 		// need to add conditional update
-	case 404, 412:
-		return errors.New("Invalid collection ,graph or document id")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -197,10 +260,8 @@ func (g *Graph) ReplaceV(col string, key string, doc interface{}, patch interfac
 	case 200, 202:
 		return nil
 		// need to add conditional update
-	case 404, 412:
-		return errors.New("Invalid collection ,graph or document id")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -221,10 +282,8 @@ func (g *Graph) PatchV(col string, key string, doc interface{}, patch interface{
 	case 200, 202:
 		return nil
 		// need to add conditional update
-	case 404, 412:
-		return errors.New("Invalid collection ,graph or document id")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -243,10 +302,8 @@ func (g *Graph) V(col string, doc interface{}) error {
 	switch res.Status() {
 	case 201, 202:
 		return nil
-	case 404:
-		return errors.New("Invalid collection or graph to save vertex")
 	default:
-		return nil
+		return statusError(res)
 	}
 }
 
@@ -266,12 +323,8 @@ func (g *Graph) GetV(col string, key string, doc interface{}) error {
 	switch res.Status() {
 	case 200:
 		return nil
-	case 404:
-		return errors.New("Invalid collection or graph")
-	case 412:
-		return errors.New("Updated")
 	default:
-		return nil
+		return statusError(res)
 	}
 
 }
@@ -293,10 +346,8 @@ func (g *Graph) RemoveVertexCol(col string) error {
 	switch res.Status() {
 	case 200:
 		return nil
-	case 400:
-		return errors.New("Cannot remove collection")
 	default:
-		return errors.New("Invalid graph")
+		return statusError(res)
 	}
 }
 
@@ -317,10 +368,8 @@ func (g *Graph) RemoveEdgeDef(col string) error {
 	switch res.Status() {
 	case 200:
 		return nil
-	case 400:
-		return errors.New("Cannot remove edge")
 	default:
-		return errors.New("Invalid graph")
+		return statusError(res)
 	}
 }
 
@@ -339,10 +388,8 @@ func (g *Graph) AddVertexCol(col string) error {
 	switch res.Status() {
 	case 200:
 		return nil
-	case 400:
-		return errors.New("Unable to add vertex collection")
 	default:
-		return errors.New("Invalid graph")
+		return statusError(res)
 	}
 }
 
@@ -359,10 +406,8 @@ func (g *Graph) AddEdgeDef(ed *EdgeDefinition) error {
 	switch res.Status() {
 	case 200:
 		return nil
-	case 400:
-		return errors.New("Unable to add edge definition")
 	default:
-		return errors.New("Invalid graph")
+		return statusError(res)
 	}
 }
 
@@ -379,10 +424,8 @@ func (g *Graph) ReplaceEdgeDef(name string, ed *EdgeDefinition) error {
 	switch res.Status() {
 	case 200:
 		return nil
-	case 400:
-		return errors.New("Unable to replace edge definition")
 	default:
-		return errors.New("Invalid graph")
+		return statusError(res)
 	}
 }
 
@@ -400,7 +443,7 @@ func (g *Graph) ListEdgesDef() ([]string, error) {
 	case 200:
 		return gr.Col, nil
 	default:
-		return []string{}, errors.New("Invalid graph")
+		return []string{}, statusError(res)
 	}
 }
 
@@ -418,7 +461,7 @@ func (g *Graph) ListVertexCol() ([]string, error) {
 	case 200:
 		return gr.Col, nil
 	default:
-		return []string{}, errors.New("Invalid graph")
+		return []string{}, statusError(res)
 	}
 }
 
@@ -475,14 +518,16 @@ func (db *Database) CreateGraph(name string, eds []EdgeDefinition, orphanVertexC
 	}
 
 	switch res.Status() {
-	case 201:
-		return &g, nil
-	case 202:
-		return &g, nil
+	case 201, 202:
+		if gr.Graph.Name == "" {
+			gr.Graph = g
+		}
+		gr.Graph.db = db
+		return &gr.Graph, nil
 	case 409:
-		return nil, errors.New("Conflic creating graph")
+		return nil, statusError(res)
 	default:
-		return nil, errors.New("Conflic creating graph")
+		return nil, statusError(res)
 	}
 }
 
@@ -499,14 +544,32 @@ func (db *Database) DropGraph(name string) error {
 	switch res.Status() {
 	case 200:
 		return nil
-	case 404:
-		return errors.New("Graph not found")
 	default:
-		return nil
+		return statusError(res)
 	}
 
 }
 
+// DropGraphCollections drops graph, removing its edge and vertex collections if they are not used by other graphs
+func (db *Database) DropGraphCollections(name string) error {
+	if name == "" {
+		return errors.New("Invalid graph name")
+	}
+	res, err := db.get("gharial", name+"?dropCollections=true", "DELETE", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200, 202:
+		return nil
+	case 404:
+		return statusError(res)
+	default:
+		return statusError(res)
+	}
+}
+
 func (db *Database) Graph(name string) *Graph {
 	var g graphResponse
 	if name == "" {
@@ -533,7 +596,7 @@ func (db *Database) ListGraphs() ([]Graph, error) {
 	case 200:
 		return gr.Graphs, nil
 	default:
-		return nil, errors.New("Unable to list graphs")
+		return nil, statusError(res)
 	}
 }
 
@@ -559,3 +622,170 @@ type Uniqueness struct {
 	Edges    string `json:"edges"`
 	Vertices string `json:"vertices"`
 }
+
+// Options of an AQL traversal
+type TraversalOptions struct {
+	// Named graph to traverse, or EdgeCollections
+	Graph           string
+	EdgeCollections []string
+	// outbound, inbound or any
+	Direction string
+	MinDepth  int
+	MaxDepth  int
+	// Optional AQL condition over v, e and p variables, ex: "p.edges[*].weight ALL > 2"
+	Filter string
+	// Optional uniqueness of vertices: none, path or global
+	UniqueVertices string
+	BFS            bool
+}
+
+// Vertices and edges visited by a traversal, Edges[i] is the edge used to reach Vertices[i]
+type TraversalResult struct {
+	Vertices []json.RawMessage `json:"vertices"`
+	Edges    []json.RawMessage `json:"edges"`
+}
+
+// Traversal prepared to run from different start vertices
+type PreparedTraversal struct {
+	db    *Database
+	aql   string
+	binds map[string]interface{}
+}
+
+// PrepareTraversal validates traversal options and renders its AQL once, to run it with Run.
+func (db *Database) PrepareTraversal(opts TraversalOptions) (*PreparedTraversal, error) {
+	t := PreparedTraversal{db: db, binds: make(map[string]interface{})}
+
+	dir, err := traversalDirection(opts.Direction)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MinDepth < 0 || opts.MaxDepth < opts.MinDepth {
+		return nil, errors.New("Invalid traversal depth")
+	}
+
+	var target string
+	if opts.Graph != "" {
+		target = "GRAPH @graph"
+		t.binds["graph"] = opts.Graph
+	} else {
+		if len(opts.EdgeCollections) == 0 {
+			return nil, errors.New("Graph or edge collections must be set")
+		}
+		for _, col := range opts.EdgeCollections {
+			if err := validColName(col); err != nil || strings.ContainsAny(col, " ,`") {
+				return nil, errors.New("Invalid edge collection: " + col)
+			}
+		}
+		target = strings.Join(opts.EdgeCollections, ", ")
+	}
+
+	aql := "LET r = (FOR v, e, p IN " + strconv.Itoa(opts.MinDepth) + ".." + strconv.Itoa(opts.MaxDepth) + " " + dir + " @start " + target
+	var options []string
+	if opts.BFS {
+		options = append(options, "bfs: true")
+	}
+	switch opts.UniqueVertices {
+	case "":
+	case "none", "path", "global":
+		options = append(options, "uniqueVertices: '"+opts.UniqueVertices+"'")
+	default:
+		return nil, errors.New("Invalid vertices uniqueness")
+	}
+	if len(options) > 0 {
+		aql += " OPTIONS { " + strings.Join(options, ", ") + " }"
+	}
+	if opts.Filter != "" {
+		aql += " FILTER " + opts.Filter
+	}
+	t.aql = aql + " RETURN { v: v, e: e }) RETURN { vertices: r[*].v, edges: r[*].e }"
+
+	return &t, nil
+}
+
+// traversalDirection returns AQL direction keyword
+func traversalDirection(direction string) (string, error) {
+	switch strings.ToLower(direction) {
+	case "outbound", "out":
+		return "OUTBOUND", nil
+	case "inbound", "in":
+		return "INBOUND", nil
+	case "any", "":
+		return "ANY", nil
+	default:
+		return "", errors.New("Invalid traversal direction")
+	}
+}
+
+// TraverseFrom runs an AQL traversal of graph from start vertex id, opts.Graph is set to graph name.
+func (g *Graph) TraverseFrom(start string, opts TraversalOptions) (*TraversalResult, error) {
+	if g.db == nil || g.Name == "" {
+		return nil, errors.New("Invalid graph to traverse")
+	}
+	opts.Graph = g.Name
+	opts.EdgeCollections = nil
+	t, err := g.db.PrepareTraversal(opts)
+	if err != nil {
+		return nil, err
+	}
+	return t.Run(start)
+}
+
+// ShortestPath returns vertices and edges of the shortest path between from and to vertex ids,
+// empty if they are not connected. Edges[0] is null, from vertex is reached without edge.
+func (g *Graph) ShortestPath(from string, to string, direction string) (*TraversalResult, error) {
+	if g.db == nil || g.Name == "" {
+		return nil, errors.New("Invalid graph to traverse")
+	}
+	if from == "" || to == "" {
+		return nil, errors.New("Invalid path vertices")
+	}
+	dir, err := traversalDirection(direction)
+	if err != nil {
+		return nil, err
+	}
+	q := NewQuery("LET r = (FOR v, e IN " + dir + " SHORTEST_PATH @from TO @to GRAPH @graph RETURN { v: v, e: e }) RETURN { vertices: r[*].v, edges: r[*].e }")
+	q.BindVars["from"] = from
+	q.BindVars["to"] = to
+	q.BindVars["graph"] = g.Name
+
+	c, err := g.db.Execute(q)
+	if err != nil {
+		return nil, err
+	}
+	var r []TraversalResult
+	err = c.decodeBatch(&r)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) != 1 {
+		return nil, errors.New("Invalid path result")
+	}
+	return &r[0], nil
+}
+
+// Run runs traversal from start vertex id
+func (t *PreparedTraversal) Run(start string) (*TraversalResult, error) {
+	if start == "" {
+		return nil, errors.New("Invalid start vertex")
+	}
+	q := NewQuery(t.aql)
+	for k, v := range t.binds {
+		q.BindVars[k] = v
+	}
+	q.BindVars["start"] = start
+
+	c, err := t.db.Execute(q)
+	if err != nil {
+		return nil, err
+	}
+	var r []TraversalResult
+	err = c.decodeBatch(&r)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) != 1 {
+		return nil, errors.New("Invalid traversal result")
+	}
+	return &r[0], nil
+}
@@ -0,0 +1,24 @@
+package arango
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareTraversal(t *testing.T) {
+	var db Database
+	tr, err := db.PrepareTraversal(TraversalOptions{Graph: "social", Direction: "out", MinDepth: 1, MaxDepth: 2, BFS: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "LET r = (FOR v, e, p IN 1..2 OUTBOUND @start GRAPH @graph OPTIONS { bfs: true } RETURN { v: v, e: e }) RETURN { vertices: r[*].v, edges: r[*].e }", tr.aql)
+	assert.Equal(t, "social", tr.binds["graph"])
+
+	tr, err = db.PrepareTraversal(TraversalOptions{EdgeCollections: []string{"knows", "likes"}, MaxDepth: 1, Filter: "v.age > 2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "LET r = (FOR v, e, p IN 0..1 ANY @start knows, likes FILTER v.age > 2 RETURN { v: v, e: e }) RETURN { vertices: r[*].v, edges: r[*].e }", tr.aql)
+
+	_, err = db.PrepareTraversal(TraversalOptions{EdgeCollections: []string{"knows RETURN 1"}, MaxDepth: 1})
+	assert.NotNil(t, err)
+	_, err = db.PrepareTraversal(TraversalOptions{Graph: "g", MinDepth: 2, MaxDepth: 1})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,126 @@
+package arango
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	nap "github.com/diegogub/napping"
+)
+
+// RequestInfo describes a request sent to the server, reported to hooks
+type RequestInfo struct {
+	Method string
+	// API endpoint without document or cursor id, like "document" or "cursor"
+	Endpoint string
+	Database string
+	// Attempt of the request, 0 unless it's retried
+	Retry int
+	// Set on response, Status is 0 if request failed
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// Hook observes every request sent by databases of a session, including cursor batches and retries.
+// Hooks are called synchronously, they must not block.
+type Hook interface {
+	OnRequest(r RequestInfo)
+	OnResponse(r RequestInfo)
+}
+
+// Warner is implemented by hooks receiving driver warnings, like a projection ignored by a query
+type Warner interface {
+	OnWarning(msg string)
+}
+
+// MetricsFunc is a Hook calling f with every response, like recording latencies by endpoint.
+//
+// Usage:
+//
+//	s.AddHook(MetricsFunc(func(r RequestInfo) {
+//		latency.WithLabelValues(r.Endpoint, strconv.Itoa(r.Status)).Observe(r.Duration.Seconds())
+//	}))
+type MetricsFunc func(r RequestInfo)
+
+func (f MetricsFunc) OnRequest(r RequestInfo) {}
+
+func (f MetricsFunc) OnResponse(r RequestInfo) {
+	f(r)
+}
+
+// LogHook returns a Hook logging every response to l, at debug level or warn if it failed, and driver warnings
+func LogHook(l *slog.Logger) Hook {
+	return logHook{l: l}
+}
+
+type logHook struct {
+	l *slog.Logger
+}
+
+func (h logHook) OnRequest(r RequestInfo) {}
+
+func (h logHook) OnWarning(msg string) {
+	h.l.Warn("arango: " + msg)
+}
+
+func (h logHook) OnResponse(r RequestInfo) {
+	level := slog.LevelDebug
+	if r.Err != nil || r.Status >= 500 {
+		level = slog.LevelWarn
+	}
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("endpoint", r.Endpoint),
+		slog.String("database", r.Database),
+		slog.Int("status", r.Status),
+		slog.Duration("duration", r.Duration),
+		slog.Int("retry", r.Retry),
+	}
+	if r.Err != nil {
+		attrs = append(attrs, slog.String("error", r.Err.Error()))
+	}
+	h.l.LogAttrs(context.Background(), level, "arango request", attrs...)
+}
+
+// AddHook adds a hook observing requests of every database of session
+func (s *Session) AddHook(h Hook) {
+	s.hooks = append(s.hooks, h)
+}
+
+// warn reports msg to session hooks implementing Warner
+func (s *Session) warn(msg string) {
+	if s == nil {
+		return
+	}
+	for _, h := range s.hooks {
+		if w, ok := h.(Warner); ok {
+			w.OnWarning(msg)
+		}
+	}
+}
+
+// observe sends request with do, reporting it to session hooks
+func (d *Database) observe(method string, resource string, retry int, do func() (*nap.Response, error)) (*nap.Response, error) {
+	if d.sess == nil || len(d.sess.hooks) == 0 {
+		return do()
+	}
+	endpoint, _, _ := strings.Cut(resource, "?")
+	r := RequestInfo{Method: method, Endpoint: endpoint, Database: d.Name, Retry: retry}
+	for _, h := range d.sess.hooks {
+		h.OnRequest(r)
+	}
+
+	t0 := time.Now()
+	res, err := do()
+	r.Duration = time.Since(t0)
+	r.Err = err
+	if res != nil {
+		r.Status = res.Status()
+	}
+	for _, h := range d.sess.hooks {
+		h.OnResponse(r)
+	}
+	return res, err
+}
@@ -0,0 +1,40 @@
+package arango
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	nap "github.com/diegogub/napping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	h := &recordHook{}
+	var metrics []RequestInfo
+	s := &Session{}
+	s.AddHook(h)
+	s.AddHook(MetricsFunc(func(r RequestInfo) { metrics = append(metrics, r) }))
+	d := Database{Name: "shop", sess: s}
+	d.SetRetry(2, time.Millisecond)
+
+	fail := errors.New("connection reset")
+	d.withRetry(context.Background(), "cursor", "123", "PUT", func() (*nap.Response, error) {
+		return nil, fail
+	})
+	assert.Equal(t, 2, len(h.reqs))
+	assert.Equal(t, 2, len(metrics))
+	assert.Equal(t, RequestInfo{Method: "PUT", Endpoint: "cursor", Database: "shop", Retry: 1}, h.reqs[1])
+	assert.Equal(t, fail, h.res[1].Err)
+	assert.Equal(t, 0, h.res[1].Status)
+	assert.Equal(t, "cursor", metrics[0].Endpoint)
+}
+
+// hook recording requests and responses
+type recordHook struct {
+	reqs, res []RequestInfo
+}
+
+func (h *recordHook) OnRequest(r RequestInfo)  { h.reqs = append(h.reqs, r) }
+func (h *recordHook) OnResponse(r RequestInfo) { h.res = append(h.res, r) }
@@ -0,0 +1,186 @@
+package arango
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	nap "github.com/diegogub/napping"
+)
+
+// Import options
+type ImportOptions struct {
+	// Action on unique key constraint errors: error (default), update, replace or ignore
+	OnDuplicate string
+	// Abort import if any document fails. ImportDocuments applies it to each batch,
+	// batches imported before the failing one are kept.
+	Complete bool
+	// Remove all documents of collection before importing
+	Overwrite bool
+	// Documents per request used by ImportDocuments, 1000 by default
+	BatchSize int
+	// Send documents as JSON lines instead of a JSON array
+	Lines bool
+}
+
+// Import result counts, documents that failed are counted in Errors and described in Details
+type ImportResult struct {
+	Created int      `json:"created"`
+	Errors  int      `json:"errors"`
+	Empty   int      `json:"empty"`
+	Updated int      `json:"updated"`
+	Ignored int      `json:"ignored"`
+	Details []string `json:"details"`
+}
+
+// Import inserts docs into collection in a single request
+func (db *Database) Import(collection string, docs []interface{}, opts ImportOptions) (*ImportResult, error) {
+	if docs == nil {
+		docs = []interface{}{}
+	}
+	body, err := json.Marshal(docs)
+	if err != nil {
+		return nil, err
+	}
+	return db.importRaw(collection, body, "list", opts)
+}
+
+// position of failed document in import details
+var importPosition = regexp.MustCompile(`at position (\d+)`)
+
+// ImportDocuments imports docs, a slice of documents, in batches of opts.BatchSize documents.
+// Results of every batch are added up, positions in Details are indexes of docs.
+// With opts.Overwrite collection is truncated only by the first batch, opts.Complete only aborts the failing batch.
+func (c *Collection) ImportDocuments(docs interface{}, opts ImportOptions) (*ImportResult, error) {
+	v := reflect.ValueOf(docs)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errors.New("Documents must be a Slice or Array")
+	}
+	size := opts.BatchSize
+	if size <= 0 {
+		size = 1000
+	}
+	typ := "list"
+	if opts.Lines {
+		typ = "documents"
+	}
+
+	total := &ImportResult{Details: []string{}}
+	var buf bytes.Buffer
+	for start := 0; start < v.Len(); start += size {
+		end := start + size
+		if end > v.Len() {
+			end = v.Len()
+		}
+
+		if err := encodeImportBatch(&buf, v, start, end, opts.Lines); err != nil {
+			return total, err
+		}
+		r, err := c.db.importRaw(c.Name, buf.Bytes(), typ, opts)
+		if err != nil {
+			return total, err
+		}
+		opts.Overwrite = false
+
+		total.Created += r.Created
+		total.Errors += r.Errors
+		total.Empty += r.Empty
+		total.Updated += r.Updated
+		total.Ignored += r.Ignored
+		total.Details = append(total.Details, shiftPositions(r.Details, start)...)
+	}
+	return total, nil
+}
+
+// encodeImportBatch writes documents start to end of v into buf, as JSON lines or a JSON array
+func encodeImportBatch(buf *bytes.Buffer, v reflect.Value, start, end int, lines bool) error {
+	buf.Reset()
+	if !lines {
+		buf.WriteByte('[')
+	}
+	for i := start; i < end; i++ {
+		b, err := json.Marshal(v.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		if i > start {
+			if lines {
+				buf.WriteByte('\n')
+			} else {
+				buf.WriteByte(',')
+			}
+		}
+		buf.Write(b)
+	}
+	if !lines {
+		buf.WriteByte(']')
+	}
+	return nil
+}
+
+// shiftPositions adds offset to document positions in import details of a batch
+func shiftPositions(details []string, offset int) []string {
+	shifted := make([]string, len(details))
+	for i, d := range details {
+		shifted[i] = importPosition.ReplaceAllStringFunc(d, func(m string) string {
+			n, _ := strconv.Atoi(importPosition.FindStringSubmatch(m)[1])
+			return "at position " + strconv.Itoa(n+offset)
+		})
+	}
+	return shifted
+}
+
+// importRaw sends body of list or documents type to import endpoint
+func (db *Database) importRaw(collection string, body []byte, typ string, opts ImportOptions) (*ImportResult, error) {
+	if collection == "" {
+		return nil, errors.New("Invalid collection name")
+	}
+	params := url.Values{}
+	params.Set("collection", collection)
+	params.Set("type", typ)
+	params.Set("details", "true")
+	switch opts.OnDuplicate {
+	case "":
+	case "error", "update", "replace", "ignore":
+		params.Set("onDuplicate", opts.OnDuplicate)
+	default:
+		return nil, errors.New("Invalid onDuplicate action " + opts.OnDuplicate)
+	}
+	if opts.Complete {
+		params.Set("complete", "true")
+	}
+	if opts.Overwrite {
+		params.Set("overwrite", "true")
+	}
+
+	var result ImportResult
+	var e ArangoError
+	res, err := db.withRetry(db.context(), "import", "", "POST", func() (*nap.Response, error) {
+		req := nap.Request{
+			Method:     "POST",
+			Url:        db.buildRequest("import?"+params.Encode(), ""),
+			Payload:    bytes.NewBuffer(body),
+			RawPayload: true,
+			Result:     &result,
+			Error:      &e,
+		}
+		return db.napSession(db.context()).Send(&req)
+	})
+	if err != nil {
+		return nil, ctxErr(db.context(), err)
+	}
+
+	switch res.Status() {
+	case 201:
+		return &result, nil
+	case 400, 404, 409:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
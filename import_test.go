@@ -0,0 +1,32 @@
+package arango
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBatches(t *testing.T) {
+	docs := []map[string]int{{"n": 0}, {"n": 1}, {"n": 2}}
+	var buf bytes.Buffer
+	assert.Nil(t, encodeImportBatch(&buf, reflect.ValueOf(docs), 1, 3, false))
+	assert.Equal(t, `[{"n":1},{"n":2}]`, buf.String())
+	assert.Nil(t, encodeImportBatch(&buf, reflect.ValueOf(docs), 0, 2, true))
+	assert.Equal(t, "{\"n\":0}\n{\"n\":1}", buf.String())
+
+	st := &serverTransport{responses: []testResponse{
+		{201, `{"created":2,"details":[]}`},
+		{201, `{"created":0,"errors":1,"details":["at position 0: creating document failed with error 'unique constraint violated'"]}`},
+	}}
+	col := &Collection{db: testDB(st), Name: "users"}
+	r, err := col.ImportDocuments(docs, ImportOptions{BatchSize: 2, Overwrite: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(st.reqs))
+	assert.Equal(t, "true", st.reqs[0].URL.Query().Get("overwrite"))
+	assert.Equal(t, "", st.reqs[1].URL.Query().Get("overwrite"))
+	assert.Equal(t, 2, r.Created)
+	assert.Equal(t, 1, r.Errors)
+	assert.Equal(t, []string{"at position 2: creating document failed with error 'unique constraint violated'"}, r.Details)
+}
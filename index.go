@@ -0,0 +1,165 @@
+package arango
+
+import (
+	"errors"
+	"time"
+)
+
+// IndexOptions describes an index to create, one of PersistentIndex, HashIndex, SkipListIndex,
+// GeoIndex, FullTextIndex or TTLIndex
+type IndexOptions interface {
+	indexBody() map[string]interface{}
+}
+
+// Persistent index, sorted and usable for ranges
+type PersistentIndex struct {
+	Fields []string
+	Unique bool
+	// documents without the fields are not indexed
+	Sparse bool
+	Name   string
+}
+
+func (i PersistentIndex) indexBody() map[string]interface{} {
+	return fieldsIndex("persistent", i.Fields, i.Unique, i.Sparse, i.Name)
+}
+
+// Hash index, an alias of persistent index in RocksDB
+type HashIndex PersistentIndex
+
+func (i HashIndex) indexBody() map[string]interface{} {
+	return fieldsIndex("hash", i.Fields, i.Unique, i.Sparse, i.Name)
+}
+
+// Skiplist index, an alias of persistent index in RocksDB
+type SkipListIndex PersistentIndex
+
+func (i SkipListIndex) indexBody() map[string]interface{} {
+	return fieldsIndex("skiplist", i.Fields, i.Unique, i.Sparse, i.Name)
+}
+
+func fieldsIndex(typ string, fields []string, unique, sparse bool, name string) map[string]interface{} {
+	body := map[string]interface{}{"type": typ, "fields": fields, "unique": unique, "sparse": sparse}
+	if name != "" {
+		body["name"] = name
+	}
+	return body
+}
+
+// Geo index over a [lat, lon] or GeoJSON field, or over latitude and longitude fields
+type GeoIndex struct {
+	Fields []string
+	// field is [lon, lat] or a GeoJSON object
+	GeoJson bool
+	Name    string
+}
+
+func (i GeoIndex) indexBody() map[string]interface{} {
+	body := map[string]interface{}{"type": "geo", "fields": i.Fields, "geoJson": i.GeoJson}
+	if i.Name != "" {
+		body["name"] = i.Name
+	}
+	return body
+}
+
+// Fulltext index over a single field
+type FullTextIndex struct {
+	Field     string
+	MinLength int
+	Name      string
+}
+
+func (i FullTextIndex) indexBody() map[string]interface{} {
+	body := map[string]interface{}{"type": "fulltext", "fields": []string{i.Field}}
+	if i.MinLength > 0 {
+		body["minLength"] = i.MinLength
+	}
+	if i.Name != "" {
+		body["name"] = i.Name
+	}
+	return body
+}
+
+// TTL index removing documents ExpireAfter the date stored in Field
+type TTLIndex struct {
+	Field       string
+	ExpireAfter time.Duration
+	Name        string
+}
+
+func (i TTLIndex) indexBody() map[string]interface{} {
+	body := map[string]interface{}{"type": "ttl", "fields": []string{i.Field}, "expireAfter": int(i.ExpireAfter / time.Second)}
+	if i.Name != "" {
+		body["name"] = i.Name
+	}
+	return body
+}
+
+// CreateIndex creates index, returning its metadata. If an equal index exists it's returned instead.
+func (c *Collection) CreateIndex(opts IndexOptions) (*Index, error) {
+	if opts == nil {
+		return nil, errors.New("Invalid index options")
+	}
+	body := opts.indexBody()
+	if fields, ok := body["fields"].([]string); !ok || len(fields) == 0 || fields[0] == "" {
+		return nil, errors.New("Index fields must not be empty")
+	}
+
+	var idx Index
+	var e ArangoError
+	res, err := c.db.send("index?collection="+c.Name, "", "POST", body, &idx, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200, 201:
+		return &idx, nil
+	case 400, 404:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// ListIndexes returns indexes of collection, including primary and edge indexes
+func (c *Collection) ListIndexes() ([]Index, error) {
+	var indexes struct {
+		Indexes []Index `json:"indexes"`
+	}
+	res, err := c.db.get("index?collection="+c.Name, "", "GET", nil, &indexes, &indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return indexes.Indexes, nil
+	case 404:
+		return nil, statusError(res)
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// DropIndex drops index by id, as collection/number
+func (c *Collection) DropIndex(id string) error {
+	if id == "" {
+		return errors.New("Invalid id")
+	}
+
+	res, err := c.db.get("index", id, "DELETE", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		return nil
+	case 404:
+		return statusError(res)
+	default:
+		return statusError(res)
+	}
+}
@@ -0,0 +1,71 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIndex(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{201, `{"id":"users/7","type":"ttl","fields":["expires"],"expireAfter":3600,"isNewlyCreated":true}`},
+	}}
+	col := &Collection{db: testDB(st), Name: "users"}
+	idx, err := col.CreateIndex(TTLIndex{Field: "expires", ExpireAfter: time.Hour, Name: "exp"})
+	assert.Nil(t, err)
+	assert.Equal(t, "users/7", idx.Id)
+	assert.Equal(t, 3600, idx.ExpireAfter)
+	assert.True(t, idx.IsNewlyCreated)
+
+	req := st.reqs[0]
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/_db/shop/_api/index", req.URL.Path)
+	assert.Equal(t, "users", req.URL.Query().Get("collection"))
+	var body map[string]interface{}
+	assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{"type": "ttl", "fields": []interface{}{"expires"}, "expireAfter": float64(3600), "name": "exp"}, body)
+
+	body = nil
+	col.CreateIndex(PersistentIndex{Fields: []string{"a", "b"}, Unique: true})
+	assert.Nil(t, json.NewDecoder(st.reqs[1].Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{"type": "persistent", "fields": []interface{}{"a", "b"}, "unique": true, "sparse": false}, body)
+
+	_, err = col.CreateIndex(HashIndex{})
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(st.reqs))
+
+	st.responses = []testResponse{{400, `{"error":true,"code":400,"errorNum":10,"errorMessage":"invalid index type"}`}}
+	st.reqs = nil
+	_, err = col.CreateIndex(GeoIndex{Fields: []string{"loc"}})
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 400, ae.Code)
+	assert.Equal(t, 10, ae.ErrorNum)
+}
+
+func TestListIndexes(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{200, `{"indexes":[{"id":"users/0","type":"primary","fields":["_key"]},{"id":"users/7","type":"persistent","fields":["name"],"sparse":true}]}`},
+	}}
+	col := &Collection{db: testDB(st), Name: "users"}
+	indexes, err := col.ListIndexes()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(indexes))
+	assert.Equal(t, "primary", indexes[0].Type)
+	assert.True(t, indexes[1].Sparse)
+	assert.Equal(t, "GET", st.reqs[0].Method)
+	assert.Equal(t, "users", st.reqs[0].URL.Query().Get("collection"))
+
+	st.responses = []testResponse{{200, `{"id":"users/7"}`}}
+	assert.Nil(t, col.DropIndex("users/7"))
+	assert.Equal(t, "DELETE", st.reqs[1].Method)
+	assert.Equal(t, "/_db/shop/_api/index/users/7", st.reqs[1].URL.Path)
+
+	st.responses = []testResponse{{404, `{"error":true,"code":404,"errorNum":1212,"errorMessage":"index not found"}`}}
+	assert.True(t, errors.Is(col.DropIndex("users/8"), ErrNotFound))
+	assert.NotNil(t, col.DropIndex(""))
+	assert.Equal(t, 3, len(st.reqs))
+}
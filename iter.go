@@ -0,0 +1,34 @@
+package arango
+
+import (
+	"iter"
+)
+
+// All returns an iterator over cursor results decoded as T, fetching next batches when needed.
+// A batch error is yielded once and stops the iteration. If the loop is stopped before the end, cursor is deleted from server.
+//
+//	for doc, err := range arango.All[User](cur) {
+//		...
+//	}
+func All[T any](c *Cursor) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var v T
+			ok, err := c.FetchNext(&v)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !ok {
+				return
+			}
+			if !yield(v, nil) {
+				if c.HasMore() {
+					c.Delete()
+				}
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package arango
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrJobPending is returned by Job.Result while the job is still running
+var ErrJobPending = errors.New("Job not finished")
+
+// Job is a request executed asynchronously by the server, its result is stored until fetched
+type Job struct {
+	Id string
+	db *Database
+}
+
+// Async sends request with x-arango-async: store, server returns at once and runs it in background.
+// Usage:
+//
+//	job, err := db.Async("POST", "cursor", query)
+//	...
+//	err = job.Result(&cursor)
+func (d *Database) Async(method string, resource string, payload interface{}) (*Job, error) {
+	header := http.Header{}
+	header.Set("x-arango-async", "store")
+	var e ArangoError
+	res, err := d.request(resource, "", method, header, payload, nil, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 202:
+		id := responseHeader(res).Get("x-arango-async-id")
+		if id == "" {
+			return nil, errors.New("Server did not return a job id")
+		}
+		return &Job{Id: id, db: d}, nil
+	default:
+		e.Code = res.Status()
+		return nil, &e
+	}
+}
+
+// ExecuteAsync executes query as an async job, Result decodes into a *Cursor
+func (d *Database) ExecuteAsync(q *Query) (*Job, error) {
+	if q == nil {
+		return nil, errors.New("Cannot execute nil query")
+	}
+	if q.Validate && !d.IsValid(q) {
+		return nil, errors.New(q.ErrorMsg)
+	}
+	return d.Async("POST", "cursor", q.projected(nil))
+}
+
+// Poll returns true if job is done and its result is ready
+func (j *Job) Poll() (bool, error) {
+	res, err := j.db.get("job", j.Id, "GET", nil, nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return true, nil
+	case 204:
+		return false, nil
+	case 404:
+		return false, statusError(res)
+	default:
+		return false, statusError(res)
+	}
+}
+
+// Result decodes job response into r, returns ErrJobPending if it's not done.
+// Result is removed from server once fetched. Error responses are returned as *ArangoError.
+func (j *Job) Result(r interface{}) error {
+	var e ArangoError
+	res, err := j.db.send("job", j.Id, "PUT", nil, r, &e)
+	if err != nil {
+		return err
+	}
+
+	header := responseHeader(res)
+	switch {
+	case res.Status() == 204 && header.Get("x-arango-async-id") == "":
+		return ErrJobPending
+	case res.Status() == 404 && header.Get("x-arango-async-id") == "":
+		// job doesn't exist, otherwise it's the response of the job
+		return statusError(res)
+	case res.Status() >= 400:
+		e.Code = res.Status()
+		e.Header = header
+		return &e
+	}
+	if c, ok := r.(*Cursor); ok {
+		c.db = j.db
+	}
+	return nil
+}
+
+// Cancel cancels running job
+func (j *Job) Cancel() error {
+	res, err := j.db.send("job", j.Id+"/cancel", "PUT", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		return nil
+	case 404:
+		return statusError(res)
+	default:
+		return statusError(res)
+	}
+}
@@ -0,0 +1,80 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteAsync(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{202, ``}}, header: http.Header{"X-Arango-Async-Id": {"42"}}}
+	db := testDB(st)
+	job, err := db.ExecuteAsync(NewQuery("FOR u IN users RETURN u"))
+	assert.Nil(t, err)
+	assert.Equal(t, "42", job.Id)
+	req := st.reqs[0]
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/_db/shop/_api/cursor", req.URL.Path)
+	assert.Equal(t, "store", req.Header.Get("x-arango-async"))
+	var body map[string]interface{}
+	assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+	assert.Equal(t, "FOR u IN users RETURN u", body["query"])
+
+	st.header = nil
+	_, err = db.Async("POST", "cursor", nil)
+	assert.NotNil(t, err)
+	st.responses = []testResponse{{403, `{"error":true,"code":403,"errorNum":11,"errorMessage":"forbidden"}`}}
+	st.reqs = nil
+	_, err = db.Async("POST", "cursor", nil)
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 11, ae.ErrorNum)
+}
+
+func TestJob(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{204, ``}, {200, ``}}}
+	job := &Job{Id: "42", db: testDB(st)}
+	done, err := job.Poll()
+	assert.Nil(t, err)
+	assert.False(t, done)
+	done, err = job.Poll()
+	assert.Nil(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "GET", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/job/42", st.reqs[0].URL.Path)
+
+	// pending job responses have no job id
+	st.responses = []testResponse{{204, ``}}
+	st.reqs = nil
+	var c Cursor
+	assert.Equal(t, ErrJobPending, job.Result(&c))
+	assert.Equal(t, "PUT", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/job/42", st.reqs[0].URL.Path)
+
+	st.responses = []testResponse{{404, `{"error":true,"code":404,"errorNum":1600,"errorMessage":"job not found"}`}}
+	assert.True(t, errors.Is(job.Result(&c), ErrNotFound))
+
+	// responses of finished jobs carry the job id
+	st.header = http.Header{"X-Arango-Async-Id": {"42"}}
+	st.responses = []testResponse{
+		{201, `{"id":"9","result":[1,2],"hasMore":false}`},
+		{404, `{"error":true,"code":404,"errorNum":1203,"errorMessage":"collection not found"}`},
+	}
+	st.reqs = nil
+	assert.Nil(t, job.Result(&c))
+	assert.Equal(t, "9", c.Id)
+	assert.Equal(t, job.db, c.db)
+	err = job.Result(&c)
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 1203, ae.ErrorNum)
+
+	st.responses = []testResponse{{200, `{"result":true}`}}
+	st.reqs = nil
+	assert.Nil(t, job.Cancel())
+	assert.Equal(t, "PUT", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/job/42/cancel", st.reqs[0].URL.Path)
+}
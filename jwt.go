@@ -0,0 +1,185 @@
+package arango
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// time before expiry a token is renewed
+const jwtRenew = time.Minute
+
+// ConnectJWT connects to Database like Connect, then authenticates all requests with a JWT renewed before expiry
+func ConnectJWT(host, user, password string, log bool) (*Session, error) {
+	s, err := Connect(host, user, password, log)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.UseJWT(user, password); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ConnectToken connects to Database sending token as bearer in all requests, like a superuser token
+// signed with the cluster secret. Token is not renewed.
+func ConnectToken(host, token string, log bool) (*Session, error) {
+	if token == "" {
+		return nil, errors.New("Invalid empty token")
+	}
+	j := &jwtTransport{next: http.DefaultTransport, token: token}
+	s, err := ConnectTransport(host, "", "", log, j)
+	if err != nil {
+		return nil, err
+	}
+	s.jwt = j
+	return s, nil
+}
+
+// UseJWT obtains a JWT from /_open/auth and sends it in all requests instead of basic auth.
+// Token is renewed before it expires or when server answers 401.
+func (s *Session) UseJWT(user, password string) error {
+	if user == "" {
+		return errors.New("Invalid empty user name")
+	}
+	j := &jwtTransport{user: user, password: password}
+	j.next = nextTransport(s.transport())
+	if err := j.login(s.host); err != nil {
+		return err
+	}
+	s.setJWT(j)
+	return nil
+}
+
+// SetToken sends token as bearer in all requests, token is not renewed
+func (s *Session) SetToken(token string) {
+	s.setJWT(&jwtTransport{next: nextTransport(s.transport()), token: token})
+}
+
+// setJWT puts j over the innermost transport, replacing current one
+func (s *Session) setJWT(j *jwtTransport) {
+	if s.jwt != nil {
+		s.jwt.mu.Lock()
+		s.jwt.user, s.jwt.password = j.user, j.password
+		s.jwt.token, s.jwt.exp = j.token, j.exp
+		s.jwt.mu.Unlock()
+		return
+	}
+	switch {
+	case s.breaker != nil:
+		s.breaker.next = j
+	case s.failover != nil:
+		s.failover.next = j
+	default:
+		s.client().Transport = j
+	}
+	s.jwt = j
+	s.nap.Userinfo = nil
+}
+
+// jwtTransport sets bearer token of requests
+type jwtTransport struct {
+	next http.RoundTripper
+	// empty when token can't be renewed
+	user     string
+	password string
+
+	mu    sync.Mutex
+	token string
+	// zero if token has no expiry
+	exp time.Time
+}
+
+func (j *jwtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := j.current(req.URL.Scheme + "://" + req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "bearer "+token)
+	res, err := j.next.RoundTrip(r)
+	if err == nil && res.StatusCode == 401 && j.user != "" {
+		// login again on next request
+		j.mu.Lock()
+		if j.token == token {
+			j.token = ""
+		}
+		j.mu.Unlock()
+	}
+	return res, err
+}
+
+// current returns token, renewing it if it's about to expire
+func (j *jwtTransport) current(host string) (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.user == "" || (j.token != "" && (j.exp.IsZero() || time.Until(j.exp) > jwtRenew)) {
+		return j.token, nil
+	}
+	return j.token, j.loginLocked(host)
+}
+
+func (j *jwtTransport) login(host string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.loginLocked(host)
+}
+
+// loginLocked gets a new token from host, must hold lock
+func (j *jwtTransport) loginLocked(host string) error {
+	body, _ := json.Marshal(map[string]string{"username": j.user, "password": j.password})
+	req, err := http.NewRequest("POST", host+"/_open/auth", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := j.next.RoundTrip(req)
+	if err != nil {
+		return downErr(err)
+	}
+	defer res.Body.Close()
+
+	var auth struct {
+		Jwt     string `json:"jwt"`
+		Message string `json:"errorMessage"`
+	}
+	json.NewDecoder(res.Body).Decode(&auth)
+	switch res.StatusCode {
+	case 200:
+		if auth.Jwt == "" {
+			return errors.New("Server did not return a token")
+		}
+		j.token = auth.Jwt
+		j.exp = jwtExpiry(auth.Jwt)
+		return nil
+	case 401:
+		return httpError(401, "Invalid user or password")
+	default:
+		return errors.New("Failed to get token, status code " + strconv.Itoa(res.StatusCode))
+	}
+}
+
+// jwtExpiry returns exp claim of token, zero if missing. Signature is not verified.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if json.Unmarshal(b, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(claims.Exp), 0)
+}
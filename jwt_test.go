@@ -0,0 +1,65 @@
+package arango
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWT(t *testing.T) {
+	a := &authTransport{exp: time.Now().Add(time.Hour), status: 200}
+	j := &jwtTransport{next: a, user: "root", password: "pw"}
+	assert.Nil(t, j.login("http://localhost:8529"))
+	assert.Equal(t, a.exp.Unix(), j.exp.Unix())
+
+	req, _ := http.NewRequest("GET", "http://localhost:8529/_api/version", nil)
+	j.RoundTrip(req)
+	j.RoundTrip(req)
+	assert.Equal(t, 1, a.logins)
+	assert.True(t, strings.HasPrefix(a.bearers[0], "bearer h."))
+	assert.Equal(t, "", req.Header.Get("Authorization"))
+
+	// rejected token is renewed on next request
+	a.status = 401
+	j.RoundTrip(req)
+	a.status = 200
+	j.RoundTrip(req)
+	assert.Equal(t, 2, a.logins)
+
+	// renewed before expiry
+	a.exp = time.Now().Add(30 * time.Second)
+	j.login("http://localhost:8529")
+	j.RoundTrip(req)
+	assert.Equal(t, 4, a.logins)
+
+	s := &authTransport{status: 200}
+	(&jwtTransport{next: s, token: "superuser"}).RoundTrip(req)
+	assert.Equal(t, []string{"bearer superuser"}, s.bearers)
+	assert.True(t, jwtExpiry("invalid").IsZero())
+}
+
+// roundTripper issuing tokens on /_open/auth and recording bearer tokens
+type authTransport struct {
+	exp     time.Time
+	logins  int
+	bearers []string
+	status  int
+}
+
+func (a *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == "/_open/auth" {
+		a.logins++
+		claims, _ := json.Marshal(map[string]int64{"exp": a.exp.Unix()})
+		token := "h." + base64.RawURLEncoding.EncodeToString(claims) + ".s" + strconv.Itoa(a.logins)
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"jwt":"` + token + `"}`)), Request: req}, nil
+	}
+	a.bearers = append(a.bearers, req.Header.Get("Authorization"))
+	return &http.Response{StatusCode: a.status, Body: http.NoBody, Request: req}, nil
+}
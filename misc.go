@@ -30,3 +30,15 @@ func isJSON(s string) bool {
 	return json.Unmarshal([]byte(s), &js) == nil
 
 }
+
+// removeNulls removes null attributes from m and nested objects
+func removeNulls(m map[string]interface{}) {
+	for k, v := range m {
+		switch v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]interface{}:
+			removeNulls(v.(map[string]interface{}))
+		}
+	}
+}
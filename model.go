@@ -36,6 +36,17 @@ func NewContext(db *Database) (*Context, error) {
 	return &c, nil
 }
 
+// Modeler is implemented by structs embedding Document, GetCollection can be replaced by
+// an arango:"collection=name" tag on any field.
+// Usage:
+//
+//	type User struct {
+//		arango.Document `arango:"collection=users"`
+//		Name            string    `json:"name" arango:"required"`
+//		Email           string    `json:"email" arango:"unique"`
+//		Role            string    `json:"role" arango:"enum=admin|user"`
+//		Updated         time.Time `json:"updated" arango:"time=update"`
+//	}
 type Modeler interface {
 	// Returns current model key
 	GetKey() string
@@ -46,6 +57,44 @@ type Modeler interface {
 	// hooks
 }
 
+// modelCollection returns model collection, from GetCollection or arango collection tag
+func modelCollection(m Modeler) string {
+	if col := m.GetCollection(); col != "" {
+		return col
+	}
+	for _, col := range Tags(m, "collection") {
+		return col
+	}
+	return ""
+}
+
+// arangoTag returns option key of arango tag, "-" if it has no value.
+// Options are comma separated, enum values are separated by |
+func arangoTag(tag reflect.StructTag, key string) string {
+	for _, opt := range strings.Split(tag.Get("arango"), ",") {
+		name, val, hasVal := strings.Cut(strings.TrimSpace(opt), "=")
+		if name != key {
+			continue
+		}
+		if !hasVal {
+			return "-"
+		}
+		if key == "enum" {
+			return strings.ReplaceAll(val, "|", ",")
+		}
+		return val
+	}
+	return ""
+}
+
+// structTag returns key tag of field, from arango tag options if missing
+func structTag(f reflect.StructField, key string) string {
+	if tag := f.Tag.Get(key); tag != "" {
+		return tag
+	}
+	return arangoTag(f.Tag, key)
+}
+
 // hook interfaces
 type PreSaver interface {
 	PreSave(c *Context)
@@ -73,7 +122,7 @@ type PostDeleter interface {
 
 //Get model
 func (c *Context) Get(m Modeler) Error {
-	col := m.GetCollection()
+	col := modelCollection(m)
 	key := m.GetKey()
 
 	c.Db.Col(col).Get(key, m)
@@ -88,7 +137,7 @@ func (c *Context) Get(m Modeler) Error {
 
 // Updates or save new Model into database
 func (c *Context) Save(m Modeler) Error {
-	col := m.GetCollection()
+	col := modelCollection(m)
 	key := m.GetKey()
 
 	// basic validation
@@ -140,6 +189,7 @@ func (c *Context) Save(m Modeler) Error {
 		}
 
 		setTimes(m.(interface{}), "save")
+		setTimes(m.(interface{}), "update")
 		e := c.Db.Col(col).Replace(key, m)
 		if e != nil {
 			// db error
@@ -161,6 +211,65 @@ func (c *Context) Save(m Modeler) Error {
 	return c.Err
 }
 
+// Update patches model in database, running update hooks
+func (c *Context) Update(m Modeler) Error {
+	col := modelCollection(m)
+	key := m.GetKey()
+	if key == "" {
+		c.Err["key"] = "invalid"
+		return c.Err
+	}
+
+	validate(m, c.Db, col, true, c.Err)
+	if len(c.Err) > 0 {
+		return c.Err
+	}
+
+	if hook, ok := m.(PreUpdater); ok {
+		hook.PreUpdate(c)
+	}
+	if len(c.Err) > 0 {
+		return c.Err
+	}
+
+	setTimes(m.(interface{}), "update")
+	e := c.Db.Col(col).Patch(key, m)
+	if e != nil {
+		c.Err["db"] = e.Error()
+		return c.Err
+	}
+
+	docerror, haserror := m.GetError()
+	if haserror {
+		c.Err["doc"] = docerror
+		return c.Err
+	}
+
+	if hook, ok := m.(PostUpdater); ok {
+		hook.PostUpdate(c)
+	}
+	return c.Err
+}
+
+// Reload reads model again from database, discarding local changes
+func (c *Context) Reload(m Modeler) Error {
+	key := m.GetKey()
+	if key == "" {
+		c.Err["key"] = "invalid"
+		return c.Err
+	}
+	e := c.Db.Col(modelCollection(m)).Get(key, m)
+	if e != nil {
+		c.Err["db"] = e.Error()
+		return c.Err
+	}
+	docerror, haserror := m.GetError()
+	if haserror {
+		c.Err["doc"] = docerror
+	}
+	return c.Err
+}
+
 type auxModelPos struct {
 	pos int
 	err Error
@@ -204,7 +313,7 @@ func (c *Context) BulkSave(models []Modeler) map[int]Error {
 
 func (c *Context) Delete(m Modeler) Error {
 	key := m.GetKey()
-	col := m.GetCollection()
+	col := modelCollection(m)
 	if key == "" {
 		//
 		c.Err["key"] = "invalid"
@@ -246,6 +355,9 @@ func Unique(m interface{}, db *Database, update bool, err Error) {
 			if ftype.Anonymous && ftype.Type.Kind() == reflect.Struct {
 				unique(field, val, db, &uniq, update, err)
 			} else {
+				if md, ok := m.(Modeler); ok && col == "-" {
+					col = modelCollection(md)
+				}
 				// validate collection name!!!!
 				validName := validColName(col)
 				if col == "-" || col == "" || validName != nil {
@@ -418,10 +530,16 @@ func Tags(obj interface{}, key string) map[string]string {
 
 	for i := 0; i < fieldsCount; i++ {
 		structField := objType.Field(i)
+		if key == "collection" {
+			if tag = arangoTag(structField.Tag, key); tag != "" {
+				tags[structField.Name] = tag
+				continue
+			}
+		}
 		if structField.Anonymous && structField.Type.Kind() == reflect.Struct {
 			getTags(structField.Type, tags, key)
 		} else {
-			tag = structField.Tag.Get(key)
+			tag = structTag(structField, key)
 			if tag != "" {
 				tags[structField.Name] = tag
 			}
@@ -443,9 +561,9 @@ func getTags(obj reflect.Type, tags map[string]string, key string) {
 	for i := 0; i < fieldsCount; i++ {
 		structField := obj.Field(i)
 		if structField.Anonymous && structField.Type.Kind() == reflect.Struct {
-			getTags(obj, tags, key)
+			getTags(structField.Type, tags, key)
 		} else {
-			tag = structField.Tag.Get(key)
+			tag = structTag(structField, key)
 			if tag != "" {
 				tags[structField.Name] = tag
 			}
@@ -474,7 +592,7 @@ func setTimes(obj interface{}, action string) {
 
 func ObjT(m Modeler) ObjTran {
 	var obt ObjTran
-	obt.Collection = m.GetCollection()
+	obt.Collection = modelCollection(m)
 	obt.Obj = m
 	return obt
 }
@@ -567,9 +685,9 @@ func (c *Context) NewRelation(main Modeler, label map[string]interface{}, edgeco
 	var act Relation
 	key := main.GetKey()
 	if key == "" {
-		validate(main, c.Db, main.GetCollection(), false, c.Err)
+		validate(main, c.Db, modelCollection(main), false, c.Err)
 	} else {
-		validate(main, c.Db, main.GetCollection(), true, c.Err)
+		validate(main, c.Db, modelCollection(main), true, c.Err)
 	}
 
 	if len(c.Err) > 0 {
@@ -584,9 +702,9 @@ func (c *Context) NewRelation(main Modeler, label map[string]interface{}, edgeco
 	for _, mod := range rel {
 		key := mod.GetKey()
 		if key == "" {
-			validate(mod, c.Db, mod.GetCollection(), false, c.Err)
+			validate(mod, c.Db, modelCollection(mod), false, c.Err)
 		} else {
-			validate(mod, c.Db, mod.GetCollection(), true, c.Err)
+			validate(mod, c.Db, modelCollection(mod), true, c.Err)
 		}
 
 		if len(c.Err) > 0 {
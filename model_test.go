@@ -0,0 +1,26 @@
+package arango
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelTags(t *testing.T) {
+	u := &taggedUser{Role: "guest"}
+	var m Modeler = u
+	assert.Equal(t, "users", modelCollection(m))
+	u.Id = "people/1"
+	assert.Equal(t, "people", modelCollection(m))
+
+	err := NewError()
+	checkRequired(u, err)
+	checkEnum(u, err)
+	assert.Equal(t, Error{"name": "invalid", "role": "invalid"}, err)
+
+	u.Name, u.Role = "diego", "admin"
+	err = NewError()
+	checkRequired(u, err)
+	checkEnum(u, err)
+	assert.Equal(t, 0, len(err))
+}
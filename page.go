@@ -0,0 +1,135 @@
+package arango
+
+import (
+	"errors"
+	"strings"
+)
+
+// BoundQuery is a query of a database, built with Database.Query
+type BoundQuery struct {
+	db *Database
+	q  *Query
+}
+
+// Query returns aql bound to database.
+// Usage:
+//
+//	var users []User
+//	page, err := db.Query("FOR u IN users SORT u.name RETURN u").Page(2, 20, &users)
+func (d *Database) Query(aql string) *BoundQuery {
+	return &BoundQuery{db: d, q: NewQuery(aql)}
+}
+
+// Bind sets bind parameter of query
+func (b *BoundQuery) Bind(name string, value interface{}) *BoundQuery {
+	b.q.BindVars[name] = value
+	return b
+}
+
+// Execute executes query
+func (b *BoundQuery) Execute() (*Cursor, error) {
+	return b.db.Execute(b.q)
+}
+
+// Page of query results
+type PagedResult struct {
+	// page number, from 1
+	Page int
+	Size int
+	// results of all pages
+	TotalCount int
+	PageCount  int
+	// decoded results, the pointer given to Page
+	Items interface{}
+}
+
+// HasNext checks if there are pages after this one
+func (p *PagedResult) HasNext() bool {
+	return p.Page < p.PageCount
+}
+
+// Page executes query returning page n, from 1, of size results decoded into r, a pointer to slice.
+// LIMIT is added before the last RETURN of the query, which must be at top level.
+func (b *BoundQuery) Page(n int, size int, r interface{}) (*PagedResult, error) {
+	if n < 1 || size < 1 {
+		return nil, errors.New("Page number and size must be positive")
+	}
+	for _, k := range []string{"pageOffset", "pageSize"} {
+		if _, ok := b.q.BindVars[k]; ok {
+			return nil, errors.New("Bind parameter " + k + " is reserved by Page")
+		}
+	}
+	i := lastReturn(b.q.Aql)
+	if i < 0 {
+		return nil, errors.New("Query must end with a top level RETURN")
+	}
+
+	pq := *b.q
+	pq.Aql = b.q.Aql[:i] + "LIMIT @pageOffset, @pageSize " + b.q.Aql[i:]
+	pq.BindVars = make(map[string]interface{})
+	for k, v := range b.q.BindVars {
+		pq.BindVars[k] = v
+	}
+	pq.BindVars["pageOffset"] = (n - 1) * size
+	pq.BindVars["pageSize"] = size
+	pq.Options = make(map[string]interface{})
+	for k, v := range b.q.Options {
+		pq.Options[k] = v
+	}
+	pq.Options["fullCount"] = true
+	if pq.Batch < size {
+		pq.Batch = size
+	}
+
+	c, err := b.db.Execute(&pq)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.FetchAll(r); err != nil {
+		return nil, err
+	}
+	total := c.FullCount()
+	return &PagedResult{Page: n, Size: size, TotalCount: total, PageCount: (total + size - 1) / size, Items: r}, nil
+}
+
+// lastReturn returns position of last RETURN keyword outside of subqueries, strings and comments, -1 if none
+func lastReturn(aql string) int {
+	last := -1
+	depth := 0
+	for i := 0; i < len(aql); i++ {
+		switch ch := aql[i]; {
+		case ch == '\'' || ch == '"' || ch == '`':
+			for i++; i < len(aql) && aql[i] != ch; i++ {
+				if aql[i] == '\\' {
+					i++
+				}
+			}
+		case strings.HasPrefix(aql[i:], "//"):
+			for i < len(aql) && aql[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(aql[i:], "/*"):
+			end := strings.Index(aql[i+2:], "*/")
+			if end < 0 {
+				return last
+			}
+			i += end + 3
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			depth--
+		case depth == 0 && len(aql)-i >= 6 && strings.EqualFold(aql[i:i+6], "RETURN"):
+			before := i == 0 || !identChar(aql[i-1])
+			after := len(aql)-i == 6 || !identChar(aql[i+6])
+			if before && after {
+				last = i
+			}
+			i += 5
+		}
+	}
+	return last
+}
+
+func identChar(c byte) bool {
+	return c == '_' || c == '@' || c == '.' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
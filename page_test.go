@@ -0,0 +1,15 @@
+package arango
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastReturn(t *testing.T) {
+	aql := "FOR u IN users LET n = (FOR o IN orders RETURN o) FILTER u.name != 'RETURN' RETURN u"
+	assert.Equal(t, strings.LastIndex(aql, "RETURN u"), lastReturn(aql))
+	assert.Equal(t, 15, lastReturn("FOR u IN users return\n{ r: u.return } // RETURN"))
+	assert.Equal(t, -1, lastReturn("FOR u IN users /* RETURN u */ REMOVE u IN users"))
+}
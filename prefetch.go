@@ -0,0 +1,39 @@
+package arango
+
+import (
+	"context"
+)
+
+// PrefetchCursor iterates cursor results while the next batch is fetched in background
+type PrefetchCursor struct {
+	c   *Cursor
+	ctx context.Context
+}
+
+// Prefetch returns an iterator over cursor fetching next batch while current one is consumed, like SetPrefetch.
+// Close must be called if iteration is not completed.
+func (c *Cursor) Prefetch(ctx context.Context) *PrefetchCursor {
+	c.SetPrefetch(true)
+	return &PrefetchCursor{c: c, ctx: ctx}
+}
+
+// FetchNext decodes next result into r, returns false when there are no more results.
+func (p *PrefetchCursor) FetchNext(r interface{}) (bool, error) {
+	return p.c.FetchNextCtx(p.ctx, r)
+}
+
+// Close stops prefetching and deletes cursor in server if it was not consumed.
+// The batch request in flight is cancelled, Close doesn't wait for it.
+func (p *PrefetchCursor) Close() error {
+	c := p.c
+	c.mu.Lock()
+	c.prefetch = false
+	c.stopPrefetch()
+	more, id := c.More, c.Id
+	c.mu.Unlock()
+	if more {
+		_, err := c.delete(id)
+		return err
+	}
+	return nil
+}
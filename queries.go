@@ -0,0 +1,152 @@
+package arango
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// Running or slow query as returned by server
+type QueryInfo struct {
+	Id       string                 `json:"id"`
+	Database string                 `json:"database"`
+	User     string                 `json:"user"`
+	Query    string                 `json:"query"`
+	BindVars map[string]interface{} `json:"bindVars"`
+	Started  string                 `json:"started"`
+	// seconds since the query started
+	RunTime float64 `json:"runTime"`
+	State   string  `json:"state"`
+	Stream  bool    `json:"stream"`
+}
+
+// RunningQueries lists queries currently running in database
+func (d *Database) RunningQueries() ([]QueryInfo, error) {
+	return d.queries("current")
+}
+
+// SlowQueries lists queries in database slow query log
+func (d *Database) SlowQueries() ([]QueryInfo, error) {
+	return d.queries("slow")
+}
+
+func (d *Database) queries(kind string) ([]QueryInfo, error) {
+	var qs []QueryInfo
+	var e ArangoError
+	res, err := d.get("query", kind, "GET", nil, &qs, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return qs, nil
+	case 400, 403:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// ClearSlowQueries clears database slow query log
+func (d *Database) ClearSlowQueries() error {
+	var e ArangoError
+	res, err := d.get("query", "slow", "DELETE", nil, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		return nil
+	case 400, 403:
+		e.Code = res.Status()
+		return &e
+	default:
+		return statusError(res)
+	}
+}
+
+// KillQuery kills running query with id
+func (d *Database) KillQuery(id string) error {
+	if id == "" {
+		return errors.New("Invalid empty query id")
+	}
+	var e ArangoError
+	res, err := d.get("query", id, "DELETE", nil, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		return nil
+	case 404:
+		return statusError(res)
+	case 400, 403:
+		e.Code = res.Status()
+		return &e
+	default:
+		return statusError(res)
+	}
+}
+
+// QueryId returns id of the running query of cursor, like a stream cursor. Server doesn't return it with the
+// cursor, so it's looked up in running queries by query string and bind variables.
+func (c *Cursor) QueryId() (string, error) {
+	if c.db == nil || c.query == nil {
+		return "", errors.New("Cursor has no query")
+	}
+	qs, err := c.db.RunningQueries()
+	if err != nil {
+		return "", err
+	}
+	return matchQuery(qs, c.query)
+}
+
+// Kill kills the running query of cursor.
+//
+// Usage:
+//
+//	go func() {
+//		<-time.After(time.Minute)
+//		cur.Kill()
+//	}()
+func (c *Cursor) Kill() error {
+	id, err := c.QueryId()
+	if err != nil {
+		return err
+	}
+	return c.db.KillQuery(id)
+}
+
+// matchQuery returns id of the only running query like q
+func matchQuery(qs []QueryInfo, q *Query) (string, error) {
+	binds, err := json.Marshal(q.BindVars)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	for _, r := range qs {
+		if r.Query != q.Aql {
+			continue
+		}
+		// bind variables are missing if server doesn't track them
+		if r.BindVars != nil {
+			b, err := json.Marshal(r.BindVars)
+			if err != nil || !bytes.Equal(b, binds) {
+				continue
+			}
+		}
+		if id != "" {
+			return "", errors.New("Query id is ambiguous, several running queries match cursor query")
+		}
+		id = r.Id
+	}
+	if id == "" {
+		return "", httpError(404, "Query is not running")
+	}
+	return id, nil
+}
@@ -0,0 +1,85 @@
+package arango
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueries(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{200, `[{"id":"17","database":"shop","query":"FOR u IN users RETURN u","bindVars":{},"runTime":2.5,"state":"executing","stream":true}]`},
+	}}
+	db := testDB(st)
+	qs, err := db.RunningQueries()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(qs))
+	assert.Equal(t, "17", qs[0].Id)
+	assert.Equal(t, 2.5, qs[0].RunTime)
+	assert.True(t, qs[0].Stream)
+	assert.Equal(t, "GET", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/query/current", st.reqs[0].URL.Path)
+
+	st.responses = []testResponse{{403, `{"error":true,"code":403,"errorNum":11,"errorMessage":"forbidden"}`}}
+	_, err = db.SlowQueries()
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 11, ae.ErrorNum)
+	assert.Equal(t, "/_db/shop/_api/query/slow", st.reqs[1].URL.Path)
+
+	st.responses = []testResponse{{200, `{"error":false}`}}
+	st.reqs = nil
+	assert.Nil(t, db.ClearSlowQueries())
+	assert.Equal(t, "DELETE", st.reqs[0].Method)
+	assert.Equal(t, "/_db/shop/_api/query/slow", st.reqs[0].URL.Path)
+
+	assert.Nil(t, db.KillQuery("17"))
+	assert.Equal(t, "DELETE", st.reqs[1].Method)
+	assert.Equal(t, "/_db/shop/_api/query/17", st.reqs[1].URL.Path)
+
+	st.responses = []testResponse{{404, `{"error":true,"code":404,"errorNum":1591,"errorMessage":"query not found"}`}}
+	assert.True(t, errors.Is(db.KillQuery("18"), ErrNotFound))
+	assert.NotNil(t, db.KillQuery(""))
+	assert.Equal(t, 3, len(st.reqs))
+}
+
+func TestCursorKill(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{
+		{200, `[{"id":"16","query":"FOR u IN users RETURN u","bindVars":{"n":2}},{"id":"17","query":"FOR u IN users RETURN u","bindVars":{"n":1}}]`},
+		{200, `{"error":false}`},
+	}}
+	q := NewQuery("FOR u IN users RETURN u")
+	q.BindVars["n"] = 1
+	c := &Cursor{db: testDB(st), query: q}
+	assert.Nil(t, c.Kill())
+	assert.Equal(t, "/_db/shop/_api/query/current", st.reqs[0].URL.Path)
+	assert.Equal(t, "DELETE", st.reqs[1].Method)
+	assert.Equal(t, "/_db/shop/_api/query/17", st.reqs[1].URL.Path)
+
+	// no running query matches
+	st.responses = []testResponse{{200, `[]`}}
+	st.reqs = nil
+	assert.True(t, errors.Is(c.Kill(), ErrNotFound))
+	assert.Equal(t, 1, len(st.reqs))
+	_, err := (&Cursor{}).QueryId()
+	assert.NotNil(t, err)
+}
+
+func TestMatchQuery(t *testing.T) {
+	q := NewQuery("FOR u IN users FILTER u.age > @age RETURN u")
+	q.BindVars["age"] = 21
+	qs := []QueryInfo{
+		{Id: "1", Query: "FOR u IN users RETURN u"},
+		{Id: "2", Query: q.Aql, BindVars: map[string]interface{}{"age": 30}},
+		{Id: "3", Query: q.Aql, BindVars: map[string]interface{}{"age": 21}},
+	}
+	id, err := matchQuery(qs, q)
+	assert.Nil(t, err)
+	assert.Equal(t, "3", id)
+
+	_, err = matchQuery(append(qs, QueryInfo{Id: "4", Query: q.Aql}), q)
+	assert.NotNil(t, err)
+	_, err = matchQuery(qs[:2], q)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
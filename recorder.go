@@ -0,0 +1,211 @@
+package arango
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// RecorderMode defines if requests are recorded or replayed
+type RecorderMode int
+
+const (
+	// send requests to server, saving responses
+	RecordMode RecorderMode = iota
+	// serve saved responses, without server
+	ReplayMode
+)
+
+// Recorder configuration
+type RecorderConfig struct {
+	Mode RecorderMode
+	// JSON file with recorded requests
+	Path string
+	// transport used to send requests in record mode, default transport if nil
+	Transport http.RoundTripper
+}
+
+// Recorder is a transport recording requests and responses to a file, or replaying them.
+// Replayed responses are matched by method, url and body, in recorded order.
+type Recorder struct {
+	conf RecorderConfig
+
+	mu           sync.Mutex
+	interactions []*interaction
+}
+
+// recorded request and response, stable file format
+type interaction struct {
+	Method string          `json:"method"`
+	Url    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+
+	Status         int             `json:"status"`
+	Header         http.Header     `json:"header,omitempty"`
+	ResponseBody   json.RawMessage `json:"response,omitempty"`
+	ResponseString string          `json:"responseText,omitempty"`
+
+	used bool
+}
+
+// NewRecorder returns a recorder, loading recorded requests in replay mode
+func NewRecorder(conf RecorderConfig) (*Recorder, error) {
+	if conf.Path == "" {
+		return nil, errors.New("Recorder path must not be empty")
+	}
+	r := &Recorder{conf: conf}
+
+	switch conf.Mode {
+	case RecordMode:
+		r.conf.Transport = nextTransport(conf.Transport)
+		return r, nil
+	case ReplayMode:
+		b, err := os.ReadFile(conf.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, err
+		}
+		return r, nil
+	default:
+		return nil, errors.New("Invalid recorder mode")
+	}
+}
+
+// SetTransport replaces the transport used to send requests, like a Recorder.
+func (s *Session) SetTransport(t http.RoundTripper) {
+	switch {
+	case s.jwt != nil:
+		s.jwt.next = nextTransport(t)
+	case s.breaker != nil:
+		s.breaker.next = nextTransport(t)
+	case s.failover != nil:
+		s.failover.next = nextTransport(t)
+	default:
+		s.client().Transport = t
+	}
+}
+
+// SetRecorder records or replays all session requests
+func (s *Session) SetRecorder(conf RecorderConfig) (*Recorder, error) {
+	if conf.Transport == nil {
+		conf.Transport = s.transport()
+	}
+	r, err := NewRecorder(conf)
+	if err != nil {
+		return nil, err
+	}
+	s.SetTransport(r)
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.conf.Mode == ReplayMode {
+		return r.replay(req, body)
+	}
+
+	res, err := r.conf.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	rbody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(rbody))
+
+	in := &interaction{Method: req.Method, Url: req.URL.String(), Status: res.StatusCode, Header: res.Header}
+	in.Body = rawOrNil(body)
+	if in.ResponseBody = rawOrNil(rbody); in.ResponseBody == nil {
+		in.ResponseString = string(rbody)
+	}
+	// non JSON request bodies are kept as string
+	if in.Body == nil && len(body) > 0 {
+		in.Body, _ = json.Marshal(string(body))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, in)
+	return res, r.save()
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url := req.URL.String()
+	for _, in := range r.interactions {
+		if in.used || in.Method != req.Method || in.Url != url || !sameBody(in.Body, body) {
+			continue
+		}
+		in.used = true
+		rbody := []byte(in.ResponseBody)
+		if in.ResponseBody == nil {
+			rbody = []byte(in.ResponseString)
+		}
+		header := in.Header
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        http.StatusText(in.Status),
+			StatusCode:    in.Status,
+			Header:        header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(rbody)),
+			ContentLength: int64(len(rbody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, errors.New("No recorded response for " + req.Method + " " + url)
+}
+
+// save writes recorded interactions to file, must hold lock
+func (r *Recorder) save() error {
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.conf.Path, b, 0644)
+}
+
+// rawOrNil returns b as raw JSON if it's valid, nil otherwise
+func rawOrNil(b []byte) json.RawMessage {
+	if len(b) == 0 || !json.Valid(b) {
+		return nil
+	}
+	return json.RawMessage(b)
+}
+
+// sameBody compares recorded body with request body
+func sameBody(recorded json.RawMessage, body []byte) bool {
+	if recorded == nil {
+		return len(body) == 0
+	}
+	if raw := rawOrNil(body); raw != nil {
+		var a, b bytes.Buffer
+		if json.Compact(&a, recorded) != nil || json.Compact(&b, raw) != nil {
+			return false
+		}
+		return bytes.Equal(a.Bytes(), b.Bytes())
+	}
+	var s string
+	return json.Unmarshal(recorded, &s) == nil && s == string(body)
+}
@@ -0,0 +1,38 @@
+package arango
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	path := t.TempDir() + "/rec.json"
+	fake := &fakeTransport{status: []int{200, 404}}
+	rec, err := NewRecorder(RecorderConfig{Mode: RecordMode, Path: path, Transport: fake})
+	assert.Nil(t, err)
+
+	req, _ := http.NewRequest("GET", "http://localhost:8529/_api/version", nil)
+	_, err = rec.RoundTrip(req)
+	assert.Nil(t, err)
+	req, _ = http.NewRequest("POST", "http://localhost:8529/_api/cursor", strings.NewReader(`{"query": "RETURN 1"}`))
+	_, err = rec.RoundTrip(req)
+	assert.Nil(t, err)
+
+	rep, err := NewRecorder(RecorderConfig{Mode: ReplayMode, Path: path})
+	assert.Nil(t, err)
+	req, _ = http.NewRequest("POST", "http://localhost:8529/_api/cursor", strings.NewReader(`{"query":"RETURN 1"}`))
+	res, err := rep.RoundTrip(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 404, res.StatusCode)
+	req, _ = http.NewRequest("GET", "http://localhost:8529/_api/version", nil)
+	res, err = rep.RoundTrip(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	// each recorded response is served once
+	_, err = rep.RoundTrip(req)
+	assert.NotNil(t, err)
+}
@@ -39,12 +39,10 @@ func (db *Database) Inventory() (*ReplicationInventory, error) {
 		return nil, err
 	}
 
-	switch res.Status() {
-	case 405, 500:
-		return nil, errors.New("Error when dumping replication info")
-	default:
-		return &rinv, nil
+	if err = statusError(res); err != nil {
+		return nil, err
 	}
+	return &rinv, nil
 }
 
 type ServerInfo struct {
@@ -65,12 +63,10 @@ func (db *Database) LoggerState() (*Logger, error) {
 		return nil, err
 	}
 
-	switch res.Status() {
-	case 405, 500:
-		return nil, errors.New("Logger state could not be determined")
-	default:
-		return &log, nil
+	if err = statusError(res); err != nil {
+		return nil, err
 	}
+	return &log, nil
 }
 
 type ApplierConf struct {
@@ -116,12 +112,10 @@ func (db *Database) Applier() (*Applier, error) {
 		return nil, err
 	}
 
-	switch res.Status() {
-	case 405, 500:
-		return nil, errors.New("Applier state could not be determined")
-	default:
-		return &appl, nil
+	if err = statusError(res); err != nil {
+		return nil, err
 	}
+	return &appl, nil
 }
 
 func (db *Database) ApplierConf() (*ApplierConf, error) {
@@ -131,12 +125,10 @@ func (db *Database) ApplierConf() (*ApplierConf, error) {
 		return nil, err
 	}
 
-	switch res.Status() {
-	case 405, 500:
-		return nil, errors.New("Applier state could not be determined")
-	default:
-		return &appConf, nil
+	if err = statusError(res); err != nil {
+		return nil, err
 	}
+	return &appConf, nil
 }
 
 func (db *Database) SetApplierConf(appconf *ApplierConf) error {
@@ -148,14 +140,7 @@ func (db *Database) SetApplierConf(appconf *ApplierConf) error {
 		return err
 	}
 
-	switch res.Status() {
-	case 400:
-		return errors.New("Configuration is incomplete or malformed or applier running")
-	case 405, 500:
-		return errors.New("Error occurred while assembling the response.")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 func (db *Database) StartReplication() error {
@@ -164,14 +149,7 @@ func (db *Database) StartReplication() error {
 		return err
 	}
 
-	switch res.Status() {
-	case 400:
-		return errors.New("Invalid applier configuration")
-	case 405, 500:
-		return errors.New("Error starting replication")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 func (db *Database) StopReplication() error {
@@ -180,12 +158,7 @@ func (db *Database) StopReplication() error {
 		return err
 	}
 
-	switch res.Status() {
-	case 405, 500:
-		return errors.New("Error stoping replication")
-	default:
-		return nil
-	}
+	return statusError(res)
 }
 
 func (db *Database) ServerID() string {
@@ -0,0 +1,145 @@
+package arango
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	nap "github.com/diegogub/napping"
+)
+
+// RetryPolicy retries idempotent requests (GET, HEAD, cursor batches and deletes with a revision)
+// failing with connection errors or retryable status codes.
+type RetryPolicy struct {
+	// Total attempts of a request, 0 or 1 disables retries
+	MaxAttempts int
+	// Wait before first retry, doubled on each one. Default 100ms
+	Backoff time.Duration
+	// Max wait between retries, no limit if 0
+	MaxBackoff time.Duration
+	// Random fraction of wait added or removed, from 0 to 1
+	Jitter float64
+	// Status codes retried, default 503
+	RetryStatus []int
+	// Retry any request, writes may be applied more than once
+	RetryWrites bool
+}
+
+// SetRetryPolicy sets retry policy of database requests
+func (d *Database) SetRetryPolicy(p RetryPolicy) {
+	if p.Backoff <= 0 {
+		p.Backoff = 100 * time.Millisecond
+	}
+	d.retries = p
+}
+
+// SetRetryPolicy sets retry policy of databases returned by DB
+func (s *Session) SetRetryPolicy(p RetryPolicy) {
+	if p.Backoff <= 0 {
+		p.Backoff = 100 * time.Millisecond
+	}
+	s.retries = p
+}
+
+// SetRetry retries idempotent requests (GET, HEAD and cursor batches) up to attempts times on connection
+// errors or 503 status, waiting base the first time and doubling it on each retry.
+func (d *Database) SetRetry(attempts int, base time.Duration) {
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	d.retries.MaxAttempts = attempts
+	d.retries.Backoff = base
+}
+
+// SetRetryWrites enables retries of any request, writes may be applied more than once.
+func (d *Database) SetRetryWrites(retry bool) {
+	d.retries.RetryWrites = retry
+}
+
+// idempotent checks if request can be retried without side effects
+func idempotent(resource string, id string, method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	case "PUT":
+		// next cursor batch
+		return resource == "cursor" && id != ""
+	default:
+		return false
+	}
+}
+
+// idempotentHeader is like idempotent, also accepting deletes of a document revision
+func idempotentHeader(resource string, id string, method string, header http.Header) bool {
+	if method == "DELETE" && (resource == "document" || resource == "edge") && header.Get("If-Match") != "" {
+		return true
+	}
+	return idempotent(resource, id, method)
+}
+
+// transient checks if request failed and may succeed if retried
+func (p *RetryPolicy) transient(res *nap.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if res == nil {
+		return false
+	}
+	if len(p.RetryStatus) == 0 {
+		return res.Status() == 503
+	}
+	for _, st := range p.RetryStatus {
+		if res.Status() == st {
+			return true
+		}
+	}
+	return false
+}
+
+// wait returns time to wait before retry i, from 1
+func (p *RetryPolicy) wait(i int) time.Duration {
+	wait := p.Backoff << (i - 1)
+	if p.MaxBackoff > 0 && (wait > p.MaxBackoff || wait <= 0) {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait += time.Duration(p.Jitter * (2*rand.Float64() - 1) * float64(wait))
+	}
+	return wait
+}
+
+// withRetry sends request with do, retrying it if retries are enabled. Stops waiting when ctx is done
+// or the next attempt would exceed its deadline, returning the last response.
+func (d *Database) withRetry(ctx context.Context, resource string, id string, method string, do func() (*nap.Response, error)) (*nap.Response, error) {
+	return d.withRetryIf(ctx, method, resource, idempotent(resource, id, method), do)
+}
+
+// withRetryIf is like withRetry, retrying only idempotent requests. Every attempt is reported to session hooks.
+func (d *Database) withRetryIf(ctx context.Context, method string, resource string, idempotent bool, do func() (*nap.Response, error)) (*nap.Response, error) {
+	attempts := 1
+	if d.retries.MaxAttempts > 1 && (d.retries.RetryWrites || idempotent) {
+		attempts = d.retries.MaxAttempts
+	}
+
+	var res *nap.Response
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			wait := d.retries.wait(i)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+				break
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return res, ctx.Err()
+			}
+		}
+		res, err = d.observe(method, resource, i, do)
+		if !d.retries.transient(res, err) || ctx.Err() != nil {
+			break
+		}
+	}
+	return res, err
+}
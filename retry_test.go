@@ -0,0 +1,51 @@
+package arango
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	nap "github.com/diegogub/napping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	assert.True(t, idempotent("document", "users/1", "GET"))
+	assert.True(t, idempotent("cursor", "123", "PUT"))
+	assert.False(t, idempotent("cursor", "", "POST"))
+	assert.False(t, idempotent("document", "users/1", "PUT"))
+
+	var d Database
+	d.SetRetry(3, time.Millisecond)
+	calls := 0
+	_, err := d.withRetry(context.Background(), "document", "users/1", "GET", func() (*nap.Response, error) {
+		calls++
+		return nil, errors.New("connection reset")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	d.withRetry(context.Background(), "document", "users", "POST", func() (*nap.Response, error) {
+		calls++
+		return nil, errors.New("connection reset")
+	})
+	assert.Equal(t, 1, calls)
+
+	h := http.Header{}
+	h.Set("If-Match", `"1"`)
+	assert.True(t, idempotentHeader("document", "users/1", "DELETE", h))
+	assert.False(t, idempotentHeader("document", "users/1", "DELETE", http.Header{}))
+
+	p := RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond, RetryStatus: []int{429}}
+	assert.Equal(t, 20*time.Millisecond, p.wait(2))
+	assert.Equal(t, 25*time.Millisecond, p.wait(3))
+	assert.True(t, p.transient(nil, errors.New("timeout")))
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		w := p.wait(1)
+		assert.True(t, w >= 5*time.Millisecond && w <= 15*time.Millisecond)
+	}
+}
@@ -1,9 +1,12 @@
 package arango
 
 import (
+	"bytes"
 	"errors"
+	"net/http"
 	"net/url"
-	"regexp"
+	"sync"
+	"time"
 
 	nap "github.com/diegogub/napping"
 )
@@ -13,6 +16,18 @@ type Session struct {
 	safe bool
 	nap  *nap.Session
 	dbs  Databases
+	// default attributes to keep in query results
+	projection []string
+	breaker    *breakerTransport
+	failover   *failoverTransport
+	jwt        *jwtTransport
+	retries    RetryPolicy
+	dateFormat DateFormat
+	// request observers
+	hooks []Hook
+	// cached server role
+	roleMu sync.Mutex
+	role   string
 }
 
 type User struct {
@@ -32,6 +47,11 @@ type auxCurrentDB struct {
 
 // Connects to Database
 func Connect(host, user, password string, log bool) (*Session, error) {
+	return ConnectTransport(host, user, password, log, nil)
+}
+
+// ConnectTransport connects to Database sending requests with transport t, like a Recorder in replay mode.
+func ConnectTransport(host, user, password string, log bool, t http.RoundTripper) (*Session, error) {
 	var sess Session
 	var s nap.Session
 	var dbs Databases
@@ -40,6 +60,9 @@ func Connect(host, user, password string, log bool) (*Session, error) {
 	s.Log = log
 	// default unsafe
 	s.UnsafeBasicAuth = true
+	if t != nil {
+		s.Client = &http.Client{Transport: t}
+	}
 
 	if user != "" {
 		s.Userinfo = url.UserPassword(user, password)
@@ -66,7 +89,7 @@ func Connect(host, user, password string, log bool) (*Session, error) {
 		sess.host = host
 		return &sess, nil
 	default:
-		return nil, errors.New("Invalid host or auth data to connect")
+		return nil, statusError(resp)
 	}
 
 }
@@ -84,12 +107,8 @@ func (s *Session) CurrentDB() (*Database, error) {
 	switch res.Status() {
 	case 200:
 		return &db.Db, nil
-	case 400:
-		return nil, errors.New("Invalid request")
-	case 404:
-		return nil, errors.New("Database not found")
 	default:
-		return nil, errors.New("Invalid error code")
+		return nil, statusError(res)
 	}
 }
 
@@ -109,89 +128,20 @@ func (s *Session) AvailableDBs() ([]string, error) {
 	switch res.Status() {
 	case 200:
 		return dbs.List, nil
-	case 400:
-		return nil, errors.New("request is invalid")
 	default:
-		return dbs.List, nil
+		return nil, statusError(res)
 	}
 
 }
 
-// Create database
+// Create database, like CreateDatabase without options
 func (s *Session) CreateDB(name string, users []User) error {
-	body := make(map[string]interface{})
-	// validate name
-	reg, err := regexp.Compile(`^[A-z]+[0-9\-_]*`)
-
-	if err != nil {
-		return err
-	}
-	if !reg.MatchString(name) {
-		return errors.New("Invalid database name")
-	}
-	if err != nil {
-		return err
-	}
-
-	body["name"] = name
-	if users != nil && len(users) > 0 {
-		body["users"] = users
-	}
-	// use _system database
-	sdb := s.DB("_system")
-
-	res, err := sdb.send("database", "", "POST", &body, nil, nil)
-	if err != nil {
-		return err
-	}
-
-	switch res.Status() {
-	case 201:
-		// update Databases
-		var dbs Databases
-		request := s.host + "/_api/database/user"
-		_, err = s.nap.Get(request, nil, &dbs, nil)
-		s.dbs.List = dbs.List
-		return nil
-	case 400:
-		return errors.New("Request parameters are invalid or database already exist")
-	case 403:
-		return errors.New("Must be _system database")
-	case 409:
-		return errors.New("Database with the specified name already exists")
-	default:
-		// update Databases
-		var dbs Databases
-		request := s.host + "/_api/database/user"
-		_, err = s.nap.Get(request, nil, &dbs, nil)
-		s.dbs.List = dbs.List
-		return nil
-	}
-
+	return s.CreateDatabase(name, users, nil)
 }
 
-//Drops database
+//Drops database, like DropDatabase
 func (s *Session) DropDB(name string) error {
-	// use _system database
-	sdb := s.DB("_system")
-
-	res, err := sdb.get("database", name, "DELETE", nil, nil, nil)
-	if err != nil {
-		return err
-	}
-
-	switch res.Status() {
-	case 201:
-		return nil
-	case 400:
-		return errors.New("Request is malformed")
-	case 403:
-		return errors.New("Request was not executed in the _system database.")
-	case 404:
-		return errors.New("Database could not be found")
-	default:
-		return nil
-	}
+	return s.DropDatabase(name)
 }
 
 // Return database
@@ -213,6 +163,7 @@ func (s *Session) DB(name string) *Database {
 	if found {
 		db.baseURL = s.host + "/_db/" + db.Name + "/_api/"
 		db.sess = s
+		db.retries = s.retries
 		// load collections
 		Collections(&db)
 	} else {
@@ -223,6 +174,103 @@ func (s *Session) DB(name string) *Database {
 
 }
 
+// ExecuteJS executes JavaScript code in the server and decodes the returned value into result.
+// Server must be started with --javascript.allow-admin-execute to enable it, otherwise ErrJSExecuteDisabled is returned.
+func (s *Session) ExecuteJS(code string, result interface{}) error {
+	if code == "" {
+		return errors.New("Invalid empty code")
+	}
+
+	req := nap.Request{
+		Method:     "POST",
+		Url:        s.host + "/_db/_system/_admin/execute?returnAsJSON=true",
+		Payload:    bytes.NewBufferString(code),
+		RawPayload: true,
+		Result:     result,
+	}
+	res, err := s.nap.Send(&req)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		return nil
+	case 404:
+		// the endpoint doesn't exist unless it's enabled
+		return &jsDisabledError{err: statusError(res)}
+	default:
+		return statusError(res)
+	}
+}
+
+// ServerTime returns server clock time
+func (s *Session) ServerTime() (time.Time, error) {
+	t, _, err := s.serverTime()
+	return t, err
+}
+
+// ClockSkew returns difference between server and local clocks, positive if server clock is ahead.
+func (s *Session) ClockSkew() (time.Duration, error) {
+	t, local, err := s.serverTime()
+	if err != nil {
+		return 0, err
+	}
+	return t.Sub(local), nil
+}
+
+// serverTime returns server time and the local time in the middle of the request
+func (s *Session) serverTime() (time.Time, time.Time, error) {
+	var st struct {
+		Time float64 `json:"time"`
+	}
+	t0 := time.Now()
+	res, err := s.nap.Get(s.host+"/_db/_system/_admin/time", nil, &st, nil)
+	t1 := time.Now()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if res.Status() != 200 {
+		return time.Time{}, time.Time{}, errors.New("Failed to get server time")
+	}
+
+	sec := int64(st.Time)
+	nsec := int64((st.Time - float64(sec)) * 1e9)
+	return time.Unix(sec, nsec), t0.Add(t1.Sub(t0) / 2), nil
+}
+
+// ServerRole returns role of the server: SINGLE, COORDINATOR, PRIMARY, AGENT or UNDEFINED
+func (s *Session) ServerRole() (string, error) {
+	s.roleMu.Lock()
+	defer s.roleMu.Unlock()
+	if s.role != "" {
+		return s.role, nil
+	}
+	var r struct {
+		Role string `json:"role"`
+	}
+	res, err := s.nap.Get(s.host+"/_db/_system/_admin/server/role", nil, &r, nil)
+	if err != nil {
+		return "", err
+	}
+	if res.Status() != 200 {
+		return "", errors.New("Failed to get server role")
+	}
+	s.role = r.Role
+	return s.role, nil
+}
+
+// SetDateFormat sets how dates are stored in documents, used by date queries. ISO strings by default.
+func (s *Session) SetDateFormat(f DateFormat) {
+	s.dateFormat = f
+}
+
+// SetProjection sets default attributes to keep from documents returned by All and Example,
+// see Query.Projection. Call it without fields to disable it.
+func (s *Session) SetProjection(fields ...string) {
+	s.projection = fields
+}
+
 func (s *Session) Safe(safe bool) {
 	s.safe = safe
 	return
@@ -0,0 +1,33 @@
+package arango
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteJS(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{200, `{"n":2}`}}}
+	s := testDB(st).sess
+	var r struct {
+		N int `json:"n"`
+	}
+	assert.Nil(t, s.ExecuteJS("return {n: 1 + 1}", &r))
+	assert.Equal(t, 2, r.N)
+	assert.Equal(t, "/_db/_system/_admin/execute", st.reqs[0].URL.Path)
+	assert.Equal(t, "true", st.reqs[0].URL.Query().Get("returnAsJSON"))
+
+	st.responses = []testResponse{{404, `{"error":true,"code":404,"errorNum":404,"errorMessage":"unknown path '/_admin/execute'"}`}}
+	err := s.ExecuteJS("return 1", nil)
+	assert.True(t, errors.Is(err, ErrJSExecuteDisabled))
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 404, ae.Code)
+
+	st.responses = []testResponse{{403, `{"error":true,"code":403,"errorNum":11,"errorMessage":"forbidden"}`}}
+	err = s.ExecuteJS("return 1", nil)
+	assert.False(t, errors.Is(err, ErrJSExecuteDisabled))
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 11, ae.ErrorNum)
+}
@@ -21,6 +21,18 @@ type Transaction struct {
 	Error bool `json:"error,omitempty"`
 	Code  int  `json:"code,omitempty"`
 	Num   int  `json:"errorNum,omitempty"`
+
+	// Stream transaction id
+	Id string    `json:"-"`
+	db *Database `json:"-"`
+	// committed or aborted by client
+	state string
+}
+
+// Running transaction info
+type TransactionInfo struct {
+	Id    string `json:"id"`
+	State string `json:"state"`
 }
 
 func NewTransaction(q string, write []string, read []string) *Transaction {
@@ -43,3 +55,195 @@ func (t *Transaction) Execute(db *Database) error {
 	err := db.ExecuteTran(t)
 	return err
 }
+
+// Options to begin a stream transaction
+type BeginOptions struct {
+	// Max transaction size in bytes
+	MaxTransactionSize int
+	// Time to wait for collection locks, rounded up to seconds
+	LockTimeout time.Duration
+	WaitForSync bool
+	// Allow reading from collections not declared, server default is true
+	AllowImplicit *bool
+}
+
+// BeginTransaction begins a stream transaction locking read and write collections.
+// Use DB to run operations inside it, then Commit or Abort it.
+func (db *Database) BeginTransaction(read []string, write []string) (*Transaction, error) {
+	return db.BeginTransactionOpts(read, write, BeginOptions{})
+}
+
+// Collections used by a stream transaction
+type TransactionCollections struct {
+	Read  []string
+	Write []string
+	// Write collections locked exclusively
+	Exclusive []string
+}
+
+// BeginTransactionOpts begins a stream transaction locking read and write collections
+func (db *Database) BeginTransactionOpts(read []string, write []string, opts BeginOptions) (*Transaction, error) {
+	return db.BeginTransactionCols(TransactionCollections{Read: read, Write: write}, opts)
+}
+
+// BeginTransactionCols begins a stream transaction locking cols
+func (db *Database) BeginTransactionCols(cols TransactionCollections, opts BeginOptions) (*Transaction, error) {
+	t := NewTransaction("", cols.Write, cols.Read)
+	if cols.Exclusive != nil {
+		t.Collections["exclusive"] = cols.Exclusive
+	}
+	body := map[string]interface{}{
+		"collections": t.Collections,
+		"waitForSync": opts.WaitForSync,
+	}
+	if opts.AllowImplicit != nil {
+		body["allowImplicit"] = *opts.AllowImplicit
+	}
+	if opts.MaxTransactionSize > 0 {
+		body["maxTransactionSize"] = opts.MaxTransactionSize
+	}
+	if opts.LockTimeout > 0 {
+		// 0 waits forever, so sub-second timeouts are rounded up
+		body["lockTimeout"] = int((opts.LockTimeout + time.Second - 1) / time.Second)
+	}
+
+	var st struct {
+		Result struct {
+			Id     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"result"`
+	}
+	res, err := db.send("transaction", "begin", "POST", body, &st, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 201:
+		t.Id = st.Result.Id
+		t.db = db
+		return t, nil
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// DB returns database sending every request inside stream transaction
+func (t *Transaction) DB() *Database {
+	if t.db == nil {
+		return nil
+	}
+	db := *t.db
+	db.trx = t.Id
+	return &db
+}
+
+// Query executes query inside stream transaction
+func (t *Transaction) Query(q *Query) (*Cursor, error) {
+	db := t.DB()
+	if db == nil {
+		return nil, errors.New("Not a stream transaction")
+	}
+	return db.Execute(q)
+}
+
+// Col returns collection whose document operations run inside stream transaction, nil if it doesn't exist.
+// Unlike Database.Col, collections are never created, it wouldn't be part of the transaction.
+func (t *Transaction) Col(name string) *Collection {
+	db := t.DB()
+	if db == nil || name == "" {
+		return nil
+	}
+	for _, c := range db.Collections {
+		if c.Name == name {
+			c.db = db
+			return &c
+		}
+	}
+	col := &Collection{db: db, Name: name}
+	if col.Refresh() != nil {
+		return nil
+	}
+	return col
+}
+
+// Commit commits stream transaction
+func (t *Transaction) Commit() error {
+	return t.finish("PUT", "committed")
+}
+
+// Abort aborts stream transaction, discarding its changes
+func (t *Transaction) Abort() error {
+	return t.finish("DELETE", "aborted")
+}
+
+func (t *Transaction) finish(method string, state string) error {
+	if t.Id == "" || t.db == nil {
+		return errors.New("Not a stream transaction")
+	}
+	if t.state != "" {
+		return errors.New("Transaction already " + t.state)
+	}
+	var e ArangoError
+	res, err := t.db.send("transaction", t.Id, method, nil, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		t.state = state
+		return nil
+	case 404:
+		return statusError(res)
+	case 409:
+		e.Code = 409
+		return &e
+	default:
+		return statusError(res)
+	}
+}
+
+// Status returns stream transaction status: running, committed or aborted
+func (t *Transaction) Status() (string, error) {
+	if t.Id == "" || t.db == nil {
+		return "", errors.New("Not a stream transaction")
+	}
+	var st struct {
+		Result struct {
+			Id     string `json:"id"`
+			Status string `json:"status"`
+		} `json:"result"`
+	}
+	res, err := t.db.get("transaction", t.Id, "GET", nil, &st, &st)
+	if err != nil {
+		return "", err
+	}
+
+	switch res.Status() {
+	case 200:
+		return st.Result.Status, nil
+	case 404:
+		return "", statusError(res)
+	default:
+		return "", statusError(res)
+	}
+}
+
+// Transactions lists running stream transactions of database
+func (db *Database) Transactions() ([]TransactionInfo, error) {
+	var list struct {
+		Transactions []TransactionInfo `json:"transactions"`
+	}
+	res, err := db.get("transaction", "", "GET", nil, &list, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return list.Transactions, nil
+	default:
+		return nil, statusError(res)
+	}
+}
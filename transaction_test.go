@@ -0,0 +1,53 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBeginTransaction(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{201, `{"result":{"id":"7","status":"running"}}`}}}
+	db := testDB(st)
+	tr, err := db.BeginTransactionOpts([]string{"users"}, nil, BeginOptions{LockTimeout: 200 * time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, "7", tr.Id)
+	var body map[string]interface{}
+	assert.Nil(t, json.NewDecoder(st.reqs[0].Body).Decode(&body))
+	assert.Equal(t, float64(1), body["lockTimeout"])
+	_, ok := body["allowImplicit"]
+	assert.False(t, ok)
+
+	implicit := false
+	db.BeginTransactionOpts(nil, nil, BeginOptions{LockTimeout: 1500 * time.Millisecond, AllowImplicit: &implicit})
+	body = nil
+	json.NewDecoder(st.reqs[1].Body).Decode(&body)
+	assert.Equal(t, float64(2), body["lockTimeout"])
+	assert.Equal(t, false, body["allowImplicit"])
+
+	st.responses = []testResponse{{404, `{"error":true,"code":404,"errorNum":1203,"errorMessage":"collection or view not found: users"}`}}
+	st.reqs = nil
+	_, err = db.BeginTransactionOpts([]string{"users"}, nil, BeginOptions{})
+	assert.True(t, errors.Is(err, ErrNotFound))
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 1203, ae.ErrorNum)
+}
+
+func TestTransactionCol(t *testing.T) {
+	st := &serverTransport{responses: []testResponse{{404, `{"error":true,"code":404,"errorNum":1203}`}}}
+	db := testDB(st)
+	db.Collections = []Collection{{Name: "users", Id: "5"}}
+	tr := &Transaction{Id: "7", db: db}
+	col := tr.Col("users")
+	assert.NotNil(t, col)
+	assert.Equal(t, "7", col.db.trx)
+	assert.Equal(t, 0, len(st.reqs))
+
+	assert.Nil(t, tr.Col("orders"))
+	assert.Equal(t, 1, len(st.reqs))
+	assert.Equal(t, "GET", st.reqs[0].Method)
+}
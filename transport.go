@@ -0,0 +1,195 @@
+package arango
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	nap "github.com/diegogub/napping"
+)
+
+// ErrCircuitOpen is returned when requests to a coordinator are stopped by the circuit breaker
+var ErrCircuitOpen = errors.New("Circuit breaker open, coordinator is failing")
+
+// client returns http client used by session
+func (s *Session) client() *http.Client {
+	if s.nap.Client == nil {
+		s.nap.Client = &http.Client{}
+	}
+	return s.nap.Client
+}
+
+// transport returns the transport sending requests, under breaker, failover and jwt
+func (s *Session) transport() http.RoundTripper {
+	switch {
+	case s.jwt != nil:
+		return s.jwt.next
+	case s.breaker != nil:
+		return s.breaker.next
+	case s.failover != nil:
+		return s.failover.next
+	case s.nap.Client != nil:
+		return s.nap.Client.Transport
+	default:
+		return nil
+	}
+}
+
+// nextTransport returns the transport to wrap, default transport if none
+func nextTransport(t http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return http.DefaultTransport
+	}
+	return t
+}
+
+// napCtx returns a copy of nap session sending requests with ctx
+func (s *Session) napCtx(ctx context.Context) *nap.Session {
+	if ctx.Done() == nil {
+		return s.nap
+	}
+	ns := *s.nap
+	c := *s.client()
+	c.Transport = ctxTransport{ctx: ctx, next: nextTransport(c.Transport)}
+	ns.Client = &c
+	return &ns
+}
+
+// ctxTransport sends requests with context
+type ctxTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t ctxTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// ctxErr returns ctx error if request failed because ctx is done
+func ctxErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return downErr(err)
+}
+
+// Circuit breaker configuration
+type CircuitBreakerConfig struct {
+	// Consecutive failures to open the breaker
+	Failures int
+	// Failures older than Window are not counted
+	Window time.Duration
+	// Time the breaker stays open, before probing coordinator with a single request
+	Cooldown time.Duration
+}
+
+// SetCircuitBreaker stops sending requests to a coordinator after conf.Failures consecutive failures (connection
+// errors or 502, 503, 504 status codes) for conf.Cooldown. After that a single request is sent, closing the breaker if it succeeds.
+func (s *Session) SetCircuitBreaker(conf CircuitBreakerConfig) {
+	if conf.Failures <= 0 {
+		conf.Failures = 5
+	}
+	if conf.Cooldown <= 0 {
+		conf.Cooldown = 10 * time.Second
+	}
+
+	if s.breaker != nil {
+		s.breaker.mu.Lock()
+		s.breaker.conf = conf
+		s.breaker.mu.Unlock()
+		return
+	}
+	// breaker goes under failover, to see the chosen coordinator
+	if s.failover != nil {
+		s.breaker = &breakerTransport{next: s.failover.next, conf: conf, hosts: make(map[string]*breakerState)}
+		s.failover.next = s.breaker
+		return
+	}
+	c := s.client()
+	s.breaker = &breakerTransport{next: nextTransport(c.Transport), conf: conf, hosts: make(map[string]*breakerState)}
+	c.Transport = s.breaker
+}
+
+type breakerTransport struct {
+	next  http.RoundTripper
+	mu    sync.Mutex
+	conf  CircuitBreakerConfig
+	hosts map[string]*breakerState
+}
+
+type breakerState struct {
+	failures  int
+	last      time.Time
+	openUntil time.Time
+	probing   bool
+}
+
+func (b *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	probe, err := b.allow(host)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.next.RoundTrip(req)
+	failed := err != nil
+	if res != nil {
+		switch res.StatusCode {
+		case 502, 503, 504:
+			failed = true
+		}
+	}
+	b.done(host, probe, failed)
+	return res, err
+}
+
+// allow checks if request can be sent to host, returns true if request is the probe of a half open breaker
+func (b *breakerTransport) allow(host string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st, ok := b.hosts[host]
+	if !ok || st.openUntil.IsZero() {
+		return false, nil
+	}
+	if time.Now().Before(st.openUntil) || st.probing {
+		return false, ErrCircuitOpen
+	}
+	st.probing = true
+	return true, nil
+}
+
+func (b *breakerTransport) done(host string, probe bool, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st, ok := b.hosts[host]
+	if !ok {
+		st = &breakerState{}
+		b.hosts[host] = st
+	}
+	now := time.Now()
+
+	if !failed {
+		st.failures = 0
+		st.openUntil = time.Time{}
+		st.probing = false
+		return
+	}
+
+	if probe {
+		st.probing = false
+		st.openUntil = now.Add(b.conf.Cooldown)
+		return
+	}
+	if b.conf.Window > 0 && now.Sub(st.last) > b.conf.Window {
+		st.failures = 0
+	}
+	st.failures++
+	st.last = now
+	if st.failures >= b.conf.Failures {
+		st.openUntil = now.Add(b.conf.Cooldown)
+	}
+}
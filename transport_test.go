@@ -0,0 +1,110 @@
+package arango
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	nap "github.com/diegogub/napping"
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripper returning given status codes, 0 returns a connection error
+type fakeTransport struct {
+	status []int
+	calls  int
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	st := f.status[f.calls%len(f.status)]
+	f.calls++
+	if st == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: st, Body: http.NoBody, Request: req}, nil
+}
+
+// roundTripper answering requests with responses, the last one is repeated
+type serverTransport struct {
+	mu        sync.Mutex
+	responses []testResponse
+	reqs      []*http.Request
+	// added to every response
+	header http.Header
+}
+
+type testResponse struct {
+	status int
+	body   string
+}
+
+func (f *serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := f.responses[len(f.responses)-1]
+	if len(f.reqs) < len(f.responses) {
+		r = f.responses[len(f.reqs)]
+	}
+	f.reqs = append(f.reqs, req)
+	h := http.Header{"Content-Type": {"application/json"}}
+	for k, v := range f.header {
+		h[k] = v
+	}
+	return &http.Response{StatusCode: r.status, Header: h, Body: io.NopCloser(strings.NewReader(r.body)), Request: req}, nil
+}
+
+// database sending requests to t
+func testDB(t http.RoundTripper) *Database {
+	s := &Session{host: "http://db", nap: &nap.Session{Client: &http.Client{Transport: t}}}
+	return &Database{Name: "shop", baseURL: s.host + "/_db/shop/_api/", sess: s}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	fake := &fakeTransport{status: []int{503}}
+	b := &breakerTransport{next: fake, conf: CircuitBreakerConfig{Failures: 2, Cooldown: 20 * time.Millisecond}, hosts: make(map[string]*breakerState)}
+	req, _ := http.NewRequest("GET", "http://localhost:8529/_api/version", nil)
+
+	b.RoundTrip(req)
+	b.RoundTrip(req)
+	_, err := b.RoundTrip(req)
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, 2, fake.calls)
+
+	// probe fails, opens again
+	time.Sleep(25 * time.Millisecond)
+	b.RoundTrip(req)
+	assert.Equal(t, 3, fake.calls)
+	_, err = b.RoundTrip(req)
+	assert.Equal(t, ErrCircuitOpen, err)
+
+	// probe succeeds, closes breaker
+	fake.status = []int{200}
+	time.Sleep(25 * time.Millisecond)
+	res, err := b.RoundTrip(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	_, err = b.RoundTrip(req)
+	assert.Nil(t, err)
+}
+
+// roundTripper blocking until request context is done
+type blockingTransport struct{}
+
+func (blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestCtxTransport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest("PUT", "http://localhost:8529/_api/cursor/1", nil)
+	_, err := ctxTransport{ctx: ctx, next: blockingTransport{}}.RoundTrip(req)
+	assert.Equal(t, context.DeadlineExceeded, ctxErr(ctx, err))
+	assert.Nil(t, ctxErr(context.Background(), nil))
+}
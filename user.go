@@ -0,0 +1,211 @@
+package arango
+
+import (
+	"errors"
+	"net/url"
+)
+
+// Access level of a user to a database or collection
+type AccessLevel string
+
+const (
+	AccessRW   AccessLevel = "rw"
+	AccessRO   AccessLevel = "ro"
+	AccessNone AccessLevel = "none"
+)
+
+// User as returned by server user API
+type UserInfo struct {
+	User   string                 `json:"user"`
+	Active bool                   `json:"active"`
+	Extra  map[string]interface{} `json:"extra"`
+}
+
+// Options to create or update a user, nil Active keeps current value or true for new users
+type UserOptions struct {
+	Password string
+	Active   *bool
+	Extra    map[string]interface{}
+}
+
+func (o UserOptions) body() map[string]interface{} {
+	body := map[string]interface{}{}
+	if o.Password != "" {
+		body["passwd"] = o.Password
+	}
+	if o.Active != nil {
+		body["active"] = *o.Active
+	}
+	if o.Extra != nil {
+		body["extra"] = o.Extra
+	}
+	return body
+}
+
+// userDB returns _system database, where users are managed
+func (s *Session) userDB() (*Database, error) {
+	db := s.DB("_system")
+	if db == nil {
+		return nil, errors.New("Invalid db, users are managed in _system database")
+	}
+	return db, nil
+}
+
+// CreateUser creates user, server must be accessed with an admin user
+func (s *Session) CreateUser(name string, opts UserOptions) (*UserInfo, error) {
+	if name == "" {
+		return nil, errors.New("Invalid empty user name")
+	}
+	db, err := s.userDB()
+	if err != nil {
+		return nil, err
+	}
+	body := opts.body()
+	body["user"] = name
+
+	var u UserInfo
+	var e ArangoError
+	res, err := db.send("user", "", "POST", body, &u, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 201:
+		return &u, nil
+	case 400, 403, 409:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// UpdateUser updates non empty options of user
+func (s *Session) UpdateUser(name string, opts UserOptions) (*UserInfo, error) {
+	if name == "" {
+		return nil, errors.New("Invalid empty user name")
+	}
+	db, err := s.userDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var u UserInfo
+	var e ArangoError
+	res, err := db.send("user", url.PathEscape(name), "PATCH", opts.body(), &u, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return &u, nil
+	case 400, 403, 404:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// DeleteUser deletes user
+func (s *Session) DeleteUser(name string) error {
+	if name == "" {
+		return errors.New("Invalid empty user name")
+	}
+	db, err := s.userDB()
+	if err != nil {
+		return err
+	}
+
+	var e ArangoError
+	res, err := db.get("user", url.PathEscape(name), "DELETE", nil, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 202:
+		return nil
+	case 403, 404:
+		e.Code = res.Status()
+		return &e
+	default:
+		return statusError(res)
+	}
+}
+
+// ListUsers lists all users
+func (s *Session) ListUsers() ([]UserInfo, error) {
+	db, err := s.userDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Result []UserInfo `json:"result"`
+	}
+	var e ArangoError
+	res, err := db.get("user", "", "GET", nil, &list, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Status() {
+	case 200:
+		return list.Result, nil
+	case 401, 403:
+		e.Code = res.Status()
+		return nil, &e
+	default:
+		return nil, statusError(res)
+	}
+}
+
+// GrantDatabaseAccess sets access level of user to database
+func (s *Session) GrantDatabaseAccess(user string, database string, level AccessLevel) error {
+	if database == "" {
+		return errors.New("Invalid empty database name")
+	}
+	return s.grant(user, url.PathEscape(database), level)
+}
+
+// GrantCollectionAccess sets access level of user to collection of database
+func (s *Session) GrantCollectionAccess(user string, database string, col string, level AccessLevel) error {
+	if database == "" || col == "" {
+		return errors.New("Invalid empty database or collection name")
+	}
+	return s.grant(user, url.PathEscape(database)+"/"+url.PathEscape(col), level)
+}
+
+func (s *Session) grant(user string, resource string, level AccessLevel) error {
+	if user == "" {
+		return errors.New("Invalid empty user name")
+	}
+	switch level {
+	case AccessRW, AccessRO, AccessNone:
+	default:
+		return errors.New("Invalid access level " + string(level))
+	}
+	db, err := s.userDB()
+	if err != nil {
+		return err
+	}
+
+	var e ArangoError
+	res, err := db.send("user", url.PathEscape(user)+"/database/"+resource, "PUT", map[string]interface{}{"grant": level}, nil, &e)
+	if err != nil {
+		return err
+	}
+
+	switch res.Status() {
+	case 200:
+		return nil
+	case 400, 403, 404:
+		e.Code = res.Status()
+		return &e
+	default:
+		return statusError(res)
+	}
+}
@@ -0,0 +1,89 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// session with _system database, user requests are sent after loading its collections
+func testUserSession(st *serverTransport, responses ...testResponse) *Session {
+	st.reqs = nil
+	st.responses = nil
+	for _, r := range responses {
+		st.responses = append(st.responses, testResponse{200, `{"result":[]}`}, r)
+	}
+	s := testDB(st).sess
+	s.dbs.List = []string{"_system"}
+	return s
+}
+
+func TestCreateUser(t *testing.T) {
+	st := &serverTransport{}
+	s := testUserSession(st, testResponse{201, `{"user":"app","active":false,"extra":{"team":"a"}}`})
+	active := false
+	u, err := s.CreateUser("app", UserOptions{Password: "secret", Active: &active, Extra: map[string]interface{}{"team": "a"}})
+	assert.Nil(t, err)
+	assert.Equal(t, &UserInfo{User: "app", Extra: map[string]interface{}{"team": "a"}}, u)
+	req := st.reqs[1]
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/_db/_system/_api/user", req.URL.Path)
+	var body map[string]interface{}
+	assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{"user": "app", "passwd": "secret", "active": false, "extra": map[string]interface{}{"team": "a"}}, body)
+
+	s = testUserSession(st, testResponse{409, `{"error":true,"code":409,"errorNum":1702,"errorMessage":"duplicate user"}`})
+	_, err = s.CreateUser("app", UserOptions{})
+	assert.True(t, errors.Is(err, ErrConflict))
+	var ae *ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, 1702, ae.ErrorNum)
+
+	// only set options are sent on update
+	s = testUserSession(st, testResponse{200, `{"user":"app/1","active":true}`})
+	_, err = s.UpdateUser("app/1", UserOptions{Password: "new"})
+	assert.Nil(t, err)
+	assert.Equal(t, "PATCH", st.reqs[1].Method)
+	assert.Equal(t, "/_db/_system/_api/user/app%2F1", st.reqs[1].URL.EscapedPath())
+	body = nil
+	assert.Nil(t, json.NewDecoder(st.reqs[1].Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{"passwd": "new"}, body)
+
+	s = testUserSession(st, testResponse{404, `{"error":true,"code":404,"errorNum":1703,"errorMessage":"user not found"}`})
+	assert.True(t, errors.Is(s.DeleteUser("app"), ErrNotFound))
+	assert.Equal(t, "DELETE", st.reqs[1].Method)
+	assert.Equal(t, "/_db/_system/_api/user/app", st.reqs[1].URL.Path)
+
+	s = testUserSession(st, testResponse{200, `{"result":[{"user":"root","active":true},{"user":"app","active":false}]}`})
+	users, err := s.ListUsers()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(users))
+	assert.Equal(t, "root", users[0].User)
+	assert.True(t, users[0].Active)
+}
+
+func TestGrantAccess(t *testing.T) {
+	st := &serverTransport{}
+	s := testUserSession(st, testResponse{200, `{"shop":"rw"}`})
+	assert.Nil(t, s.GrantDatabaseAccess("app", "shop", AccessRW))
+	req := st.reqs[1]
+	assert.Equal(t, "PUT", req.Method)
+	assert.Equal(t, "/_db/_system/_api/user/app/database/shop", req.URL.Path)
+	var body map[string]interface{}
+	assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+	assert.Equal(t, map[string]interface{}{"grant": "rw"}, body)
+
+	s = testUserSession(st, testResponse{200, `{"shop/users":"ro"}`})
+	assert.Nil(t, s.GrantCollectionAccess("app", "shop", "users", AccessRO))
+	assert.Equal(t, "/_db/_system/_api/user/app/database/shop/users", st.reqs[1].URL.Path)
+
+	s = testUserSession(st, testResponse{404, `{"error":true,"code":404,"errorNum":1703,"errorMessage":"user not found"}`})
+	assert.True(t, errors.Is(s.GrantDatabaseAccess("nobody", "shop", AccessNone), ErrNotFound))
+
+	st.reqs = nil
+	assert.NotNil(t, s.GrantDatabaseAccess("app", "shop", "admin"))
+	assert.NotNil(t, s.GrantCollectionAccess("app", "shop", "", AccessRO))
+	assert.Equal(t, 0, len(st.reqs))
+}